@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolDetails returns the lines -annotations prints under a tool in
+// list-tools: its title, annotations, icons and _meta, leaving out those
+// it doesn't have. A hint the server left out is shown with its default.
+func toolDetails(tool *mcp.Tool) []string {
+	var lines []string
+	if tool.Title != "" {
+		lines = append(lines, fmt.Sprintf("title: %q", tool.Title))
+	}
+	if a := tool.Annotations; a != nil {
+		line := fmt.Sprintf("annotations: readOnly=%t destructive=%s idempotent=%t openWorld=%s",
+			a.ReadOnlyHint, optionalHint(a.DestructiveHint), a.IdempotentHint, optionalHint(a.OpenWorldHint))
+		if a.Title != "" {
+			line += fmt.Sprintf(" title=%q", a.Title)
+		}
+		lines = append(lines, line)
+	}
+	for _, icon := range tool.Icons {
+		line := "icon: " + iconSource(icon.Source)
+		if icon.MIMEType != "" {
+			line += " " + icon.MIMEType
+		}
+		if len(icon.Sizes) > 0 {
+			line += " sizes=" + strings.Join(icon.Sizes, ",")
+		}
+		if icon.Theme != "" {
+			line += " theme=" + string(icon.Theme)
+		}
+		lines = append(lines, line)
+	}
+	if len(tool.Meta) > 0 {
+		meta, err := json.Marshal(tool.Meta)
+		if err != nil {
+			meta = []byte(err.Error())
+		}
+		lines = append(lines, "_meta: "+string(meta))
+	}
+	return lines
+}
+
+// optionalHint formats a hint whose default is true.
+func optionalHint(hint *bool) string {
+	if hint == nil {
+		return "true (default)"
+	}
+	return fmt.Sprint(*hint)
+}
+
+// iconSource shortens a data URI to its media type and length, which says
+// more than the start of its base64.
+func iconSource(source string) string {
+	header, data, ok := strings.Cut(source, ",")
+	if !ok || !strings.HasPrefix(header, "data:") {
+		return source
+	}
+	return fmt.Sprintf("%s,... (%d bytes)", header, len(data))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToolDetails(t *testing.T) {
+	no := false
+	tests := []struct {
+		name string
+		tool *mcp.Tool
+		want []string
+	}{
+		{name: "plain", tool: &mcp.Tool{Name: "echo"}},
+		{
+			name: "hints set",
+			tool: &mcp.Tool{Name: "a", Annotations: &mcp.ToolAnnotations{
+				ReadOnlyHint: true, DestructiveHint: &no, IdempotentHint: true, OpenWorldHint: &no, Title: "A",
+			}},
+			want: []string{`annotations: readOnly=true destructive=false idempotent=true openWorld=false title="A"`},
+		},
+		{
+			name: "hints default",
+			tool: &mcp.Tool{Name: "b", Title: "B", Annotations: &mcp.ToolAnnotations{}},
+			want: []string{
+				`title: "B"`,
+				"annotations: readOnly=false destructive=true (default) idempotent=false openWorld=true (default)",
+			},
+		},
+		{
+			name: "icons and meta",
+			tool: &mcp.Tool{
+				Name: "c",
+				Icons: []mcp.Icon{
+					{Source: "data:image/svg+xml;base64,PHN2Zz4=", MIMEType: "image/svg+xml", Sizes: []string{"any"}, Theme: mcp.IconThemeDark},
+					{Source: "https://example.com/c.png", Sizes: []string{"16x16", "32x32"}},
+				},
+				Meta: mcp.Meta{"k": map[string]any{"n": 1}},
+			},
+			want: []string{
+				"icon: data:image/svg+xml;base64,... (8 bytes) image/svg+xml sizes=any theme=dark",
+				"icon: https://example.com/c.png sizes=16x16,32x32",
+				`_meta: {"k":{"n":1}}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toolDetails(tt.tool))
+		})
+	}
+}
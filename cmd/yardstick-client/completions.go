@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// completionRef builds the reference complete asks about: a prompt by
+// name, or a resource template by URI template. Exactly one must be set.
+func completionRef(promptName, uri string) (*mcp.CompleteReference, error) {
+	switch {
+	case promptName != "" && uri != "":
+		return nil, fmt.Errorf("set only one of -prompt and -uri")
+	case promptName != "":
+		return &mcp.CompleteReference{Type: "ref/prompt", Name: promptName}, nil
+	case uri != "":
+		return &mcp.CompleteReference{Type: "ref/resource", URI: uri}, nil
+	}
+	return nil, fmt.Errorf("-prompt or -uri is required")
+}
+
+// Complete asks the server to complete value for argument arg of ref, with
+// resolved holding the arguments already chosen, and prints the
+// suggestions.
+func (c *Client) Complete(
+	ctx context.Context, ref *mcp.CompleteReference, arg, value string, resolved map[string]string,
+) error {
+	params := &mcp.CompleteParams{Ref: ref, Argument: mcp.CompleteParamsArgument{Name: arg, Value: value}}
+	if len(resolved) > 0 {
+		params.Context = &mcp.CompleteContext{Arguments: resolved}
+	}
+	result, err := c.session.Complete(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to complete %s: %w", arg, err)
+	}
+
+	fmt.Println(formatCompletion(arg, value, result.Completion))
+	for _, v := range result.Completion.Values {
+		fmt.Printf("  %s\n", v)
+	}
+	return nil
+}
+
+// formatCompletion renders the header of a completion as
+// `Completions for <arg> "<value>" (<n>[ of <total>][, more available]):`.
+// total is left out when the server doesn't send one.
+func formatCompletion(arg, value string, result mcp.CompletionResultDetails) string {
+	count := fmt.Sprint(len(result.Values))
+	if result.Total > 0 {
+		count += fmt.Sprintf(" of %d", result.Total)
+	}
+	if result.HasMore {
+		count += ", more available"
+	}
+	return fmt.Sprintf("Completions for %s %q (%s):", arg, value, count)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletionRef(t *testing.T) {
+	ref, err := completionRef("conversation", "")
+	require.NoError(t, err)
+	assert.Equal(t, &mcp.CompleteReference{Type: "ref/prompt", Name: "conversation"}, ref)
+
+	ref, err = completionRef("", "test://{x}")
+	require.NoError(t, err)
+	assert.Equal(t, &mcp.CompleteReference{Type: "ref/resource", URI: "test://{x}"}, ref)
+
+	_, err = completionRef("", "")
+	assert.EqualError(t, err, "-prompt or -uri is required")
+	_, err = completionRef("conversation", "test://{x}")
+	assert.EqualError(t, err, "set only one of -prompt and -uri")
+}
+
+func TestFormatCompletion(t *testing.T) {
+	tests := []struct {
+		result mcp.CompletionResultDetails
+		want   string
+	}{
+		{mcp.CompletionResultDetails{Values: []string{"a", "b"}, Total: 2}, `Completions for x "a" (2 of 2):`},
+		{mcp.CompletionResultDetails{Values: []string{"a"}, Total: 5, HasMore: true},
+			`Completions for x "a" (1 of 5, more available):`},
+		{mcp.CompletionResultDetails{Values: []string{"a"}, HasMore: true}, `Completions for x "a" (1, more available):`},
+		{mcp.CompletionResultDetails{}, `Completions for x "a" (0):`},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, formatCompletion("x", "a", tt.result))
+	}
+}
+
+func TestClient_Complete(t *testing.T) {
+	var got *mcp.CompleteParams
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		CompletionHandler: func(_ context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+			got = req.Params
+			return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{
+				Values: []string{req.Params.Argument.Value + "1"}, Total: 2, HasMore: true,
+			}}, nil
+		},
+	})
+	mockServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := NewClient(Config{Transport: "streamable-http", URL: mockServer.URL})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	ref := &mcp.CompleteReference{Type: "ref/resource", URI: "test://{a}/{b}"}
+	require.NoError(t, client.Complete(ctx, ref, "b", "x", map[string]string{"a": "1"}))
+	assert.Equal(t, ref, got.Ref)
+	assert.Equal(t, mcp.CompleteParamsArgument{Name: "b", Value: "x"}, got.Argument)
+	assert.Equal(t, &mcp.CompleteContext{Arguments: map[string]string{"a": "1"}}, got.Context)
+
+	require.NoError(t, client.Complete(ctx, ref, "a", "", nil))
+	assert.Nil(t, got.Context)
+}
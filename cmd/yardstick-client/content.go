@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"  // register decoders for image.DecodeConfig
+	_ "image/jpeg" // register decoders for image.DecodeConfig
+	_ "image/png"  // register decoders for image.DecodeConfig
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// mimeExtensions maps the binary MIME types MCP servers commonly return
+// to file extensions. mime.ExtensionsByType is not used because its
+// answers depend on the host's MIME database.
+var mimeExtensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"audio/wav":       ".wav",
+	"audio/x-wav":     ".wav",
+	"audio/mpeg":      ".mp3",
+	"audio/ogg":       ".ogg",
+	"application/pdf": ".pdf",
+}
+
+// renderContent writes each content item of a tool result to w: text as
+// is, and everything else as a one-line summary. If outputDir is set,
+// binary content (images, audio and blob resources) is also written there
+// as content-<index><ext>.
+func renderContent(w io.Writer, content []mcp.Content, outputDir string) error {
+	for i, item := range content {
+		if err := renderItem(w, i, item, outputDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderItem writes one content item the way renderContent does, saving
+// binary content as content-<index><ext>.
+func renderItem(w io.Writer, index int, item mcp.Content, outputDir string) error {
+	var data []byte
+	var mimeType, uri string
+	switch c := item.(type) {
+	case *mcp.TextContent:
+		fmt.Fprintln(w, c.Text)
+	case *mcp.ImageContent:
+		data, mimeType = c.Data, c.MIMEType
+		fmt.Fprintf(w, "[image] %s, %d bytes%s\n", c.MIMEType, len(c.Data), imageDimensions(c.Data))
+	case *mcp.AudioContent:
+		data, mimeType = c.Data, c.MIMEType
+		fmt.Fprintf(w, "[audio] %s, %d bytes\n", c.MIMEType, len(c.Data))
+	case *mcp.ResourceLink:
+		size := ""
+		if c.Size != nil {
+			size = fmt.Sprintf("%d bytes", *c.Size)
+		}
+		fmt.Fprintf(w, "[resource link] %s %q%s\n", c.URI, c.Name, details(c.MIMEType, size))
+	case *mcp.EmbeddedResource:
+		r := c.Resource
+		if r == nil {
+			fmt.Fprintln(w, "[resource] (empty)")
+			return nil
+		}
+		if r.Blob == nil {
+			fmt.Fprintf(w, "[resource] %s%s\n%s\n", r.URI, details(r.MIMEType), r.Text)
+			return nil
+		}
+		data, mimeType, uri = r.Blob, r.MIMEType, r.URI
+		fmt.Fprintf(w, "[resource] %s%s\n", r.URI, details(r.MIMEType, fmt.Sprintf("%d bytes", len(r.Blob))))
+	default:
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to marshal content %d: %w", index, err)
+		}
+		fmt.Fprintln(w, string(raw))
+	}
+
+	if data == nil || outputDir == "" {
+		return nil
+	}
+	file, err := writeContent(outputDir, index, data, contentExtension(mimeType, uri))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "  saved to %s\n", file)
+	return nil
+}
+
+func imageDimensions(data []byte) string {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(", %dx%d", config.Width, config.Height)
+}
+
+// details formats the non-empty parts as " (a, b)", or "" if there are
+// none.
+func details(parts ...string) string {
+	var nonEmpty []string
+	for _, p := range parts {
+		if p != "" {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(nonEmpty, ", ") + ")"
+}
+
+// contentExtension picks a file extension for binary content: the one its
+// MIME type implies, else the one on its URI's path, else .bin.
+func contentExtension(mimeType, uri string) string {
+	if ext, ok := mimeExtensions[mimeType]; ok {
+		return ext
+	}
+	if u, err := url.Parse(uri); err == nil {
+		if ext := path.Ext(u.Path); ext != "" {
+			return ext
+		}
+	}
+	return ".bin"
+}
+
+func writeContent(dir string, index int, data []byte, ext string) (string, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	file := filepath.Join(dir, fmt.Sprintf("content-%d%s", index, ext))
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write content %d: %w", index, err)
+	}
+	return file, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height))))
+	return buf.Bytes()
+}
+
+func TestRenderContent(t *testing.T) {
+	img := testPNG(t, 4, 3)
+	size := int64(1234)
+	content := []mcp.Content{
+		&mcp.TextContent{Text: "hello"},
+		&mcp.ImageContent{Data: img, MIMEType: "image/png"},
+		&mcp.AudioContent{Data: []byte("RIFF...."), MIMEType: "audio/wav"},
+		&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///notes.txt", MIMEType: "text/plain", Text: "notes"}},
+		&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///data.parquet", Blob: []byte{1, 2, 3}}},
+		&mcp.ResourceLink{URI: "file:///big.png", Name: "big.png", MIMEType: "image/png", Size: &size},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, renderContent(&out, content, ""))
+	assert.Equal(t, "hello\n"+
+		"[image] image/png, "+strconv.Itoa(len(img))+" bytes, 4x3\n"+
+		"[audio] audio/wav, 8 bytes\n"+
+		"[resource] file:///notes.txt (text/plain)\nnotes\n"+
+		"[resource] file:///data.parquet (3 bytes)\n"+
+		"[resource link] file:///big.png \"big.png\" (image/png, 1234 bytes)\n",
+		out.String())
+}
+
+func TestRenderContent_OutputDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	img := testPNG(t, 2, 2)
+	content := []mcp.Content{
+		&mcp.TextContent{Text: "not saved"},
+		&mcp.ImageContent{Data: img, MIMEType: "image/png"},
+		&mcp.AudioContent{Data: []byte("wav"), MIMEType: "audio/wav"},
+		&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "file:///data.parquet", Blob: []byte{1, 2, 3}}},
+		&mcp.EmbeddedResource{Resource: &mcp.ResourceContents{URI: "yardstick://blob", Blob: []byte{4}}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, renderContent(&out, content, dir))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"content-1.png", "content-2.wav", "content-3.parquet", "content-4.bin"}, names)
+
+	saved, err := os.ReadFile(filepath.Join(dir, "content-1.png"))
+	require.NoError(t, err)
+	assert.Equal(t, img, saved)
+	assert.Contains(t, out.String(), "saved to "+filepath.Join(dir, "content-1.png"))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	elicitAccept  = "accept"
+	elicitDecline = "decline"
+	elicitCancel  = "cancel"
+)
+
+// elicitationResponse is one scripted answer to an elicitation request.
+type elicitationResponse struct {
+	Action string `json:"action"`
+	// Content is the form to submit with accept. Left out, the form is
+	// filled in from its schema (see fillForm).
+	Content map[string]any `json:"content,omitempty"`
+}
+
+// parseElicitation parses -elicitation: an action, or @ and the path of a
+// JSON file holding a response or a list of them.
+func parseElicitation(spec string) ([]elicitationResponse, error) {
+	path, ok := strings.CutPrefix(spec, "@")
+	if !ok {
+		responses := []elicitationResponse{{Action: spec}}
+		return responses, checkElicitation(responses)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - the path is from user configuration, this is intentional
+	if err != nil {
+		return nil, fmt.Errorf("failed to read elicitation script: %w", err)
+	}
+	var responses []elicitationResponse
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		err = json.Unmarshal(data, &responses)
+	} else {
+		responses = make([]elicitationResponse, 1)
+		err = json.Unmarshal(data, &responses[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse elicitation script %s: %w", path, err)
+	}
+	if len(responses) == 0 {
+		return nil, fmt.Errorf("elicitation script %s has no responses", path)
+	}
+	return responses, checkElicitation(responses)
+}
+
+func checkElicitation(responses []elicitationResponse) error {
+	for i, r := range responses {
+		if !slices.Contains([]string{elicitAccept, elicitDecline, elicitCancel}, r.Action) {
+			return fmt.Errorf("unknown elicitation action %q in response %d: valid values are %s, %s, %s",
+				r.Action, i+1, elicitAccept, elicitDecline, elicitCancel)
+		}
+		if r.Content != nil && r.Action != elicitAccept {
+			return fmt.Errorf("response %d has content, which only accept can have", i+1)
+		}
+	}
+	return nil
+}
+
+// elicitationHandler returns an ElicitationHandler that answers with
+// responses in turn, repeating the last once they run out. Each request is
+// printed as it is answered.
+func elicitationHandler(responses []elicitationResponse) func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	var mu sync.Mutex
+	next := 0
+	return func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+		mu.Lock()
+		response := responses[min(next, len(responses)-1)]
+		next++
+		mu.Unlock()
+
+		result := &mcp.ElicitResult{Action: response.Action, Content: response.Content}
+		if result.Action == elicitAccept && result.Content == nil {
+			result.Content = fillForm(req.Params.RequestedSchema)
+		}
+		line := fmt.Sprintf("[elicitation] %q -> %s", req.Params.Message, result.Action)
+		if result.Content != nil {
+			content, err := json.Marshal(result.Content)
+			if err != nil {
+				return nil, err
+			}
+			line += " " + string(content)
+		}
+		fmt.Println(line)
+		return result, nil
+	}
+}
+
+// fillForm fills in every property of a form's schema, deterministically:
+// with its default, else its first allowed value, else a value of its type
+// that satisfies its format and bounds.
+func fillForm(schema any) map[string]any {
+	content := map[string]any{}
+	s, _ := schema.(map[string]any)
+	properties, _ := s["properties"].(map[string]any)
+	for name, p := range properties {
+		if prop, ok := p.(map[string]any); ok {
+			content[name] = fillValue(prop)
+		}
+	}
+	return content
+}
+
+func fillValue(prop map[string]any) any {
+	if v, ok := prop["default"]; ok {
+		return v
+	}
+	if enum, ok := prop["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	if oneOf, ok := prop["oneOf"].([]any); ok && len(oneOf) > 0 {
+		if option, ok := oneOf[0].(map[string]any); ok {
+			return option["const"]
+		}
+	}
+	switch prop["type"] {
+	case "boolean":
+		return true
+	case "number", "integer":
+		return fillNumber(prop)
+	case "array":
+		items, _ := prop["items"].(map[string]any)
+		if value := fillValue(items); value != nil {
+			return []any{value}
+		}
+		return []any{}
+	case "string":
+		return fillString(prop)
+	}
+	return nil
+}
+
+// fillNumber returns the minimum, if there is one, else the nearest
+// allowed value to 0.
+func fillNumber(prop map[string]any) float64 {
+	if minimum, ok := prop["minimum"].(float64); ok {
+		return minimum
+	}
+	if maximum, ok := prop["maximum"].(float64); ok && maximum < 0 {
+		return maximum
+	}
+	return 0
+}
+
+// fillString returns an example of the string's format, or "yardstick"
+// cut to its maximum length.
+func fillString(prop map[string]any) string {
+	switch prop["format"] {
+	case "email":
+		return "yardstick@example.com"
+	case "uri":
+		return "https://example.com/yardstick"
+	case "date":
+		return "2025-01-01"
+	case "date-time":
+		return "2025-01-01T00:00:00Z"
+	}
+	value := "yardstick"
+	if maxLength, ok := prop["maxLength"].(float64); ok && int(maxLength) < len(value) {
+		value = value[:int(maxLength)]
+	}
+	return value
+}
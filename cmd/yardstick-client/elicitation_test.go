@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseElicitation(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+		return "@" + path
+	}
+
+	responses, err := parseElicitation("decline")
+	require.NoError(t, err)
+	assert.Equal(t, []elicitationResponse{{Action: elicitDecline}}, responses)
+
+	responses, err = parseElicitation(write("one.json", `{"action": "accept", "content": {"name": "Ada"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []elicitationResponse{{Action: elicitAccept, Content: map[string]any{"name": "Ada"}}}, responses)
+
+	responses, err = parseElicitation(write("list.json", ` [{"action": "cancel"}, {"action": "accept"}]`))
+	require.NoError(t, err)
+	assert.Equal(t, []elicitationResponse{{Action: elicitCancel}, {Action: elicitAccept}}, responses)
+
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{"ignore", `unknown elicitation action "ignore" in response 1`},
+		{"", `unknown elicitation action ""`},
+		{write("bad.json", `[{"action": "accept"}, {"action": "skip"}]`), `unknown elicitation action "skip" in response 2`},
+		{write("content.json", `{"action": "decline", "content": {}}`), "only accept can have"},
+		{write("empty.json", `[]`), "has no responses"},
+		{write("invalid.json", `{"action":`), "failed to parse elicitation script"},
+		{"@" + filepath.Join(dir, "missing.json"), "failed to read elicitation script"},
+	}
+	for _, tt := range tests {
+		_, err := parseElicitation(tt.spec)
+		assert.ErrorContains(t, err, tt.wantErr, tt.spec)
+	}
+}
+
+func TestFillForm(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":      map[string]any{"type": "string"},
+			"code":      map[string]any{"type": "string", "maxLength": float64(4)},
+			"email":     map[string]any{"type": "string", "format": "email"},
+			"site":      map[string]any{"type": "string", "format": "uri"},
+			"born":      map[string]any{"type": "string", "format": "date"},
+			"seen":      map[string]any{"type": "string", "format": "date-time"},
+			"color":     map[string]any{"type": "string", "enum": []any{"red", "green"}},
+			"size":      map[string]any{"type": "string", "oneOf": []any{map[string]any{"const": "s", "title": "Small"}}},
+			"tags":      map[string]any{"type": "array", "items": map[string]any{"type": "string", "enum": []any{"a", "b"}}},
+			"age":       map[string]any{"type": "integer", "minimum": float64(18)},
+			"offset":    map[string]any{"type": "number", "maximum": float64(-1)},
+			"count":     map[string]any{"type": "integer"},
+			"subscribe": map[string]any{"type": "boolean", "default": false},
+			"agree":     map[string]any{"type": "boolean"},
+		},
+	}
+	assert.Equal(t, map[string]any{
+		"name":      "yardstick",
+		"code":      "yard",
+		"email":     "yardstick@example.com",
+		"site":      "https://example.com/yardstick",
+		"born":      "2025-01-01",
+		"seen":      "2025-01-01T00:00:00Z",
+		"color":     "red",
+		"size":      "s",
+		"tags":      []any{"a"},
+		"age":       float64(18),
+		"offset":    float64(-1),
+		"count":     float64(0),
+		"subscribe": false,
+		"agree":     true,
+	}, fillForm(schema))
+	assert.Empty(t, fillForm(nil))
+}
+
+func TestElicitationHandler(t *testing.T) {
+	handler := elicitationHandler([]elicitationResponse{
+		{Action: elicitAccept, Content: map[string]any{"name": "Ada"}},
+		{Action: elicitAccept},
+		{Action: elicitDecline},
+	})
+	req := &mcp.ElicitRequest{Params: &mcp.ElicitParams{Message: "Who?", RequestedSchema: map[string]any{
+		"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}},
+	}}}
+
+	var actions []string
+	var contents []map[string]any
+	for range 4 {
+		result, err := handler(context.Background(), req)
+		require.NoError(t, err)
+		actions = append(actions, result.Action)
+		contents = append(contents, result.Content)
+	}
+	assert.Equal(t, []string{elicitAccept, elicitAccept, elicitDecline, elicitDecline}, actions)
+	assert.Equal(t, []map[string]any{{"name": "Ada"}, {"name": "yardstick"}, nil, nil}, contents)
+}
+
+// TestClient_Elicitation answers a tool's elicitation request over
+// streamable HTTP, where the server sends elicitation/create, and SSE,
+// where the request comes back as an input request in the tool's result.
+func TestClient_Elicitation(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "elicit", InputSchema: &jsonschema.Schema{Type: "object"}},
+		func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			answer, ok := req.Params.InputResponses["elicit"].(*mcp.ElicitResult)
+			if !ok {
+				return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{"elicit": &mcp.ElicitParams{
+					Message: "Who are you?",
+					RequestedSchema: &jsonschema.Schema{Type: "object", Required: []string{"email"}, Properties: map[string]*jsonschema.Schema{
+						"email": {Type: "string", Format: "email"},
+					}},
+				}}}, nil
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{
+				&mcp.TextContent{Text: answer.Action + " " + answer.Content["email"].(string)},
+			}}, nil
+		})
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mux.Handle("/sse", mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	for _, transport := range []string{transportStreamableHTTP, transportSSE} {
+		t.Run(transport, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			path := map[string]string{transportStreamableHTTP: "/mcp", transportSSE: "/sse"}[transport]
+			client := NewClient(Config{Transport: transport, URL: mockServer.URL + path, Elicitation: elicitAccept})
+			require.NoError(t, client.Connect(ctx))
+			defer client.Close()
+
+			result, err := client.session.CallTool(ctx, &mcp.CallToolParams{Name: "elicit", Arguments: map[string]any{}})
+			require.NoError(t, err)
+			require.False(t, result.IsError, "%v", result.Content)
+			require.Len(t, result.Content, 1)
+			assert.Equal(t, "accept yardstick@example.com", result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+
+	client := NewClient(Config{Transport: transportSSE, URL: mockServer.URL + "/sse", Elicitation: "maybe"})
+	assert.ErrorContains(t, client.Connect(context.Background()), `unknown elicitation action "maybe"`)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// The lists a list_changed notification can name, in the order WatchLists
+// re-fetches them.
+const (
+	listNameTools     = "tools"
+	listNameResources = "resources"
+	listNamePrompts   = "prompts"
+)
+
+// listChanged prints a list_changed notification as it arrives and marks
+// the list for WatchLists to re-fetch. Like resourceUpdated, it must not
+// block.
+func (c *Client) listChanged(list string) {
+	c.listChanges.Add(1)
+	fmt.Printf("[list_changed] %s\n", list)
+	c.markStale(list)
+}
+
+// markStale queues lists for WatchLists to re-fetch.
+func (c *Client) markStale(lists ...string) {
+	c.listsMu.Lock()
+	for _, list := range lists {
+		c.staleLists[list] = true
+	}
+	c.listsMu.Unlock()
+	select {
+	case c.listsChanged <- struct{}{}:
+	default:
+	}
+}
+
+// takeStale returns the lists queued for re-fetching, in order, and clears
+// the queue.
+func (c *Client) takeStale() []string {
+	c.listsMu.Lock()
+	defer c.listsMu.Unlock()
+	var stale []string
+	for _, list := range []string{listNameTools, listNameResources, listNamePrompts} {
+		if c.staleLists[list] {
+			stale = append(stale, list)
+		}
+	}
+	clear(c.staleLists)
+	return stale
+}
+
+// WatchLists prints the tools, resources and prompts the server advertises,
+// then re-fetches and prints each list whenever the server says it
+// changed, until ctx is done. Several notifications for a list that arrive before
+// it is re-fetched cause one re-fetch. The client must have been connected
+// with WatchLists set. Running out of time is how a watch normally ends,
+// so it is not an error.
+func (c *Client) WatchLists(ctx context.Context) error {
+	if !c.config.WatchLists {
+		return fmt.Errorf("client was not connected with WatchLists set")
+	}
+	fmt.Println("Watching tool, resource and prompt lists")
+	var advertised []string
+	if caps := c.session.InitializeResult().Capabilities; caps != nil {
+		if caps.Tools != nil {
+			advertised = append(advertised, listNameTools)
+		}
+		if caps.Resources != nil {
+			advertised = append(advertised, listNameResources)
+		}
+		if caps.Prompts != nil {
+			advertised = append(advertised, listNamePrompts)
+		}
+	}
+	c.markStale(advertised...)
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Printf("Stopped watching lists after %d change(s)\n", c.listChanges.Load())
+			return nil
+		case <-c.listsChanged:
+			for _, list := range c.takeStale() {
+				if err := c.printList(ctx, list); err != nil && ctx.Err() == nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (c *Client) printList(ctx context.Context, list string) error {
+	switch list {
+	case listNameTools:
+		return c.ListTools(ctx)
+	case listNameResources:
+		return c.ListResources(ctx)
+	default:
+		return c.ListPrompts(ctx)
+	}
+}
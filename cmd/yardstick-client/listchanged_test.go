@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_StaleLists(t *testing.T) {
+	client := NewClient(Config{})
+	client.staleLists = map[string]bool{}
+	client.listsChanged = make(chan struct{}, 1)
+
+	client.listChanged(listNamePrompts)
+	client.listChanged(listNameTools)
+	client.listChanged(listNamePrompts)
+	assert.Equal(t, int64(3), client.listChanges.Load())
+	assert.Len(t, client.listsChanged, 1)
+	assert.Equal(t, []string{listNameTools, listNamePrompts}, client.takeStale())
+	assert.Empty(t, client.takeStale())
+
+	require.EqualError(t, client.WatchLists(context.Background()), "client was not connected with WatchLists set")
+}
+
+func TestClient_WatchLists(t *testing.T) {
+	var toolLists, promptLists atomic.Int64
+	// 2026-07-28 sessions only get list_changed for what the server
+	// advertised when they started listening.
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		Capabilities: &mcp.ServerCapabilities{Prompts: &mcp.PromptCapabilities{ListChanged: true}},
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "fixed"}, func(
+		context.Context, *mcp.CallToolRequest, map[string]any,
+	) (*mcp.CallToolResult, map[string]any, error) {
+		return nil, map[string]any{}, nil
+	})
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			switch method {
+			case "tools/list":
+				toolLists.Add(1)
+			case "prompts/list":
+				promptLists.Add(1)
+			}
+			return next(ctx, method, req)
+		}
+	})
+	getServer := func(_ *http.Request) *mcp.Server { return server }
+	mux := http.NewServeMux()
+	mux.Handle("/sse", mcp.NewSSEHandler(getServer, nil))
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(getServer, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	for _, transport := range []string{"streamable-http", "sse"} {
+		t.Run(transport, func(t *testing.T) {
+			toolLists.Store(0)
+			promptLists.Store(0)
+			path := map[string]string{"sse": "/sse", "streamable-http": "/mcp"}[transport]
+			client := NewClient(Config{Transport: transport, URL: mockServer.URL + path, WatchLists: true})
+			require.NoError(t, client.Connect(context.Background()))
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			go func() {
+				ticker := time.NewTicker(20 * time.Millisecond)
+				defer ticker.Stop()
+				for added := false; ; added = !added {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						if added {
+							server.RemovePrompts("dynamic")
+						} else {
+							server.AddPrompt(&mcp.Prompt{Name: "dynamic"},
+								func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+									return &mcp.GetPromptResult{}, nil
+								})
+						}
+					}
+				}
+			}()
+			require.NoError(t, client.WatchLists(ctx))
+			assert.Positive(t, client.listChanges.Load())
+			// The tools are listed once up front, the prompts again
+			// whenever they change.
+			assert.Equal(t, int64(1), toolLists.Load())
+			assert.Greater(t, promptLists.Load(), int64(1))
+		})
+	}
+}
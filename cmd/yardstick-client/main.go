@@ -7,20 +7,32 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/exec"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
 )
 
 const (
 	transportStdio          = "stdio"
 	transportSSE            = "sse"
 	transportStreamableHTTP = "streamable-http"
+	transportWebSocket      = "websocket"
 
 	defaultAddress = "localhost"
+	defaultSSEPath = "/sse"
+	defaultMCPPath = "/mcp"
+	defaultWSPath  = "/ws"
+
+	// progressToken is the token -progress attaches to call-tool.
+	progressToken = "yardstick-client"
 
 	// protocolVersionModern is the MCP protocol version that introduced
 	// stricter session semantics under which the server rejects Ping.
@@ -34,9 +46,50 @@ type Config struct {
 	Transport string
 	Address   string
 	Port      int
-	Command   string
-	Args      []string
-	Timeout   time.Duration
+	// URL, if set, is the full endpoint of the HTTP-based transport and
+	// overrides Address, Port and the path below.
+	URL string
+	// SSEPath, MCPPath and WSPath are the endpoint paths of the sse,
+	// streamable-http and websocket transports, including any base path
+	// prefix.
+	SSEPath string
+	MCPPath string
+	WSPath  string
+	Command string
+	Args    []string
+	Timeout time.Duration
+	// OutputDir, if set, is where call-tool and read-resource write binary
+	// content (images, audio and blob resources) from the result.
+	OutputDir string
+	// Progress, if set, attaches a progressToken to call-tool and prints
+	// the server's progress notifications as they arrive.
+	Progress bool
+	// Annotations, if set, has list-tools print each tool's title,
+	// annotations, icons and _meta.
+	Annotations bool
+	// CancelAfter is how long cancel-tool lets the call run before
+	// cancelling it.
+	CancelAfter time.Duration
+	// LogLevel, if set, is the minimum level of log messages to ask the
+	// server for; those it sends are printed as they arrive.
+	LogLevel string
+	// WatchLists asks for the server's list_changed notifications, which
+	// watch-lists needs. On the 2026-07-28 protocol that opens a
+	// subscriptions/listen stream, so other actions leave it unset.
+	WatchLists bool
+	// Sampling, if set, is how the client answers the server's sampling
+	// requests: echo or reverse (see samplingHandler). Unset, the client
+	// doesn't declare the sampling capability.
+	Sampling string
+	// Elicitation, if set, is how the client answers the server's
+	// elicitation requests: an action, or @ and a file of scripted
+	// responses (see parseElicitation). Unset, the client doesn't declare
+	// the elicitation capability.
+	Elicitation string
+	// Roots are the roots the client advertises, and ChangeRoots, if set,
+	// those call-tool changes them to before calling the tool again.
+	Roots       []*mcp.Root
+	ChangeRoots []*mcp.Root
 }
 
 // Client represents an MCP client
@@ -44,6 +97,24 @@ type Client struct {
 	config  Config
 	client  *mcp.Client
 	session *mcp.ClientSession
+
+	// updates counts the notifications/resources/updated received, and
+	// updated holds a pending signal for WatchResource to re-read the
+	// resource. One pending signal is enough: a re-read sees every update
+	// before it.
+	updates atomic.Int64
+	updated chan string
+
+	// listChanges counts the list_changed notifications received.
+	// staleLists holds the lists WatchLists has yet to re-fetch, and
+	// listsChanged a pending signal that there are some.
+	listChanges  atomic.Int64
+	listsMu      sync.Mutex
+	staleLists   map[string]bool
+	listsChanged chan struct{}
+
+	// roots are the roots currently advertised.
+	roots []*mcp.Root
 }
 
 // NewClient creates a new MCP client
@@ -65,6 +136,8 @@ func (c *Client) Connect(ctx context.Context) error {
 		transport, err = c.connectSSE()
 	case transportStreamableHTTP:
 		transport, err = c.connectStreamableHTTP()
+	case transportWebSocket:
+		transport, err = c.connectWebSocket()
 	default:
 		return fmt.Errorf("unsupported transport type: %s", c.config.Transport)
 	}
@@ -73,18 +146,68 @@ func (c *Client) Connect(ctx context.Context) error {
 		return fmt.Errorf("failed to create transport: %w", err)
 	}
 
+	c.updated = make(chan string, 1)
+	opts := &mcp.ClientOptions{ResourceUpdatedHandler: c.resourceUpdated}
+	if c.config.Progress {
+		opts.ProgressNotificationHandler = printProgress
+	}
+	if c.config.LogLevel != "" {
+		opts.LoggingMessageHandler = printLogMessage
+	}
+	if c.config.Sampling != "" {
+		handler, err := samplingHandler(c.config.Sampling)
+		if err != nil {
+			return err
+		}
+		opts.CreateMessageHandler = handler
+	}
+	if c.config.Elicitation != "" {
+		responses, err := parseElicitation(c.config.Elicitation)
+		if err != nil {
+			return err
+		}
+		opts.ElicitationHandler = elicitationHandler(responses)
+	}
+	c.staleLists = map[string]bool{}
+	c.listsChanged = make(chan struct{}, 1)
+	if c.config.WatchLists {
+		opts.ToolListChangedHandler = func(context.Context, *mcp.ToolListChangedRequest) { c.listChanged(listNameTools) }
+		opts.ResourceListChangedHandler = func(context.Context, *mcp.ResourceListChangedRequest) {
+			c.listChanged(listNameResources)
+		}
+		opts.PromptListChangedHandler = func(context.Context, *mcp.PromptListChangedRequest) {
+			c.listChanged(listNamePrompts)
+		}
+	}
 	c.client = mcp.NewClient(&mcp.Implementation{
 		Name:    "yardstick-client",
 		Version: "1.0.0",
-	}, nil)
+	}, opts)
+	c.roots = c.config.Roots
+	c.client.AddRoots(c.roots...)
 	session, err := c.client.Connect(ctx, transport, nil)
 	if err != nil {
 		return err
 	}
 	c.session = session
+
+	// Modern sessions have no logging/setLevel; toolParams sends the level
+	// with each call instead.
+	if c.config.LogLevel != "" && !c.modern() {
+		err := session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: mcp.LoggingLevel(c.config.LogLevel)})
+		if err != nil {
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+	}
 	return nil
 }
 
+// modern reports whether the session negotiated protocolVersionModern or
+// later.
+func (c *Client) modern() bool {
+	return c.session.InitializeResult().ProtocolVersion >= protocolVersionModern
+}
+
 // connectStdio creates a stdio transport connection
 func (c *Client) connectStdio() (mcp.Transport, error) {
 	if c.config.Command == "" {
@@ -96,20 +219,37 @@ func (c *Client) connectStdio() (mcp.Transport, error) {
 	return &mcp.CommandTransport{Command: cmd}, nil
 }
 
+// endpoint returns the URL of an HTTP-based transport: Config.URL if set,
+// otherwise path (or def, if path is empty) on Address and Port.
+func (c *Client) endpoint(path, def string) string {
+	if c.config.URL != "" {
+		return c.config.URL
+	}
+	if path == "" {
+		path = def
+	}
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(c.config.Address, strconv.Itoa(c.config.Port)), path)
+}
+
 // connectSSE creates an SSE transport connection
 //
 //nolint:unparam
 func (c *Client) connectSSE() (mcp.Transport, error) {
-	url := fmt.Sprintf("http://%s:%d/sse", c.config.Address, c.config.Port)
-	return &mcp.SSEClientTransport{Endpoint: url}, nil
+	return &mcp.SSEClientTransport{Endpoint: c.endpoint(c.config.SSEPath, defaultSSEPath)}, nil
 }
 
 // connectStreamableHTTP creates a streamable HTTP transport connection
 //
 //nolint:unparam
 func (c *Client) connectStreamableHTTP() (mcp.Transport, error) {
-	url := fmt.Sprintf("http://%s:%d/mcp", c.config.Address, c.config.Port)
-	return &mcp.StreamableClientTransport{Endpoint: url}, nil
+	return &mcp.StreamableClientTransport{Endpoint: c.endpoint(c.config.MCPPath, defaultMCPPath)}, nil
+}
+
+// connectWebSocket creates a WebSocket transport connection
+//
+//nolint:unparam
+func (c *Client) connectWebSocket() (mcp.Transport, error) {
+	return &wsconn.ClientTransport{URL: c.endpoint(c.config.WSPath, defaultWSPath)}, nil
 }
 
 // Close closes the client connection
@@ -122,52 +262,220 @@ func (c *Client) Close() error {
 
 // ListTools lists all available tools from the server
 func (c *Client) ListTools(ctx context.Context) error {
-	tools, err := c.session.ListTools(ctx, &mcp.ListToolsParams{})
+	tools, pages, err := c.listTools(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list tools: %w", err)
 	}
 
-	fmt.Printf("Available tools (%d):\n", len(tools.Tools))
-	for _, tool := range tools.Tools {
+	fmt.Printf("Available tools (%d, %d page(s)):\n", len(tools), pages)
+	for _, tool := range tools {
 		fmt.Printf("  - %s: %s\n", tool.Name, tool.Description)
+		if c.config.Annotations {
+			for _, line := range toolDetails(tool) {
+				fmt.Printf("      %s\n", line)
+			}
+		}
 	}
 	return nil
 }
 
-// CallTool calls a tool on the server
-func (c *Client) CallTool(ctx context.Context, toolName string, arguments map[string]interface{}) error {
-	result, err := c.session.CallTool(ctx, &mcp.CallToolParams{
+// toolParams builds the parameters of a call to toolName, with a
+// progress token if -progress is set, and the log level if -log-level is
+// set on a modern session
+func (c *Client) toolParams(toolName string, arguments map[string]interface{}) *mcp.CallToolParams {
+	params := &mcp.CallToolParams{
 		Name:      toolName,
 		Arguments: arguments,
-	})
+	}
+	if c.config.Progress {
+		params.SetProgressToken(progressToken)
+	}
+	if c.config.LogLevel != "" && c.modern() {
+		if params.Meta == nil {
+			params.Meta = mcp.Meta{}
+		}
+		params.Meta[mcp.MetaKeyLogLevel] = c.config.LogLevel
+	}
+	return params
+}
+
+// CallTool calls a tool on the server
+func (c *Client) CallTool(ctx context.Context, toolName string, arguments map[string]interface{}) error {
+	result, err := c.session.CallTool(ctx, c.toolParams(toolName, arguments))
 	if err != nil {
 		return fmt.Errorf("failed to call tool %s: %w", toolName, err)
 	}
 
-	contentJSON, err := json.Marshal(result.Content)
-	if err != nil {
-		fmt.Printf("Failed to marshal content to JSON: %v\n", err)
-	} else {
-		fmt.Printf("%v\n", string(contentJSON))
+	if result.IsError {
+		fmt.Printf("Tool %s returned an error:\n", toolName)
 	}
+	return renderContent(os.Stdout, result.Content, c.config.OutputDir)
+}
 
-	return nil
+// CancelTool calls a tool on the server and cancels the call after delay,
+// which makes the SDK send the server a notifications/cancelled for it. A
+// call that finishes first is reported like CallTool's.
+func (c *Client) CancelTool(
+	ctx context.Context, toolName string, arguments map[string]interface{}, delay time.Duration,
+) error {
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	timer := time.AfterFunc(delay, cancel)
+	defer timer.Stop()
+
+	start := time.Now()
+	result, err := c.session.CallTool(callCtx, c.toolParams(toolName, arguments))
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case err == nil:
+		fmt.Printf("Tool %s returned after %s, before it was cancelled:\n", toolName, elapsed)
+		return renderContent(os.Stdout, result.Content, c.config.OutputDir)
+	case ctx.Err() == nil && callCtx.Err() != nil:
+		fmt.Printf("Cancelled tool %s after %s\n", toolName, elapsed)
+		return nil
+	default:
+		return fmt.Errorf("failed to call tool %s: %w", toolName, err)
+	}
+}
+
+// resourceUpdated prints a notifications/resources/updated as it arrives
+// and signals WatchResource. It must not block: WatchResource's re-reads
+// need the connection this handler is called from.
+func (c *Client) resourceUpdated(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+	c.updates.Add(1)
+	fmt.Printf("[updated] %s\n", req.Params.URI)
+	select {
+	case c.updated <- req.Params.URI:
+	default:
+	}
+}
+
+// WatchResource subscribes to a resource and, until ctx is done, re-reads
+// and prints it whenever the server says it was updated. Running out of
+// time is how a watch normally ends, so it is not an error.
+func (c *Client) WatchResource(ctx context.Context, uri string) error {
+	if err := c.session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", uri, err)
+	}
+	fmt.Printf("Watching %s\n", uri)
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Printf("Stopped watching %s after %d update(s)\n", uri, c.updates.Load())
+			unsubscribeCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if err := c.session.Unsubscribe(unsubscribeCtx, &mcp.UnsubscribeParams{URI: uri}); err != nil {
+				log.Printf("Failed to unsubscribe from %s: %v", uri, err)
+			}
+			return nil
+		case updated := <-c.updated:
+			if updated != uri {
+				continue
+			}
+			if err := c.ReadResource(ctx, uri); err != nil && ctx.Err() == nil {
+				return err
+			}
+		}
+	}
+}
+
+// printProgress prints a progress notification as it arrives
+func printProgress(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+	fmt.Println(formatProgress(req.Params))
+}
+
+// printLogMessage prints a server log message as it arrives
+func printLogMessage(_ context.Context, req *mcp.LoggingMessageRequest) {
+	fmt.Println(formatLogMessage(req.Params))
+}
+
+// formatLogMessage renders a log message as
+// "[log] <level>[ <logger>]: <data>", with data that isn't a string as JSON.
+func formatLogMessage(p *mcp.LoggingMessageParams) string {
+	prefix := "[log] " + string(p.Level)
+	if p.Logger != "" {
+		prefix += " " + p.Logger
+	}
+	data, ok := p.Data.(string)
+	if !ok {
+		raw, err := json.Marshal(p.Data)
+		if err != nil {
+			data = fmt.Sprint(p.Data)
+		} else {
+			data = string(raw)
+		}
+	}
+	return prefix + ": " + data
+}
+
+// formatProgress renders a progress notification as
+// "[progress] <progress>[/<total>][ <message>]".
+func formatProgress(p *mcp.ProgressNotificationParams) string {
+	progress := strconv.FormatFloat(p.Progress, 'f', -1, 64)
+	if p.Total > 0 {
+		progress += "/" + strconv.FormatFloat(p.Total, 'f', -1, 64)
+	}
+	if p.Message != "" {
+		progress += " " + p.Message
+	}
+	return "[progress] " + progress
 }
 
 // ListResources lists all available resources from the server
 func (c *Client) ListResources(ctx context.Context) error {
-	resources, err := c.session.ListResources(ctx, &mcp.ListResourcesParams{})
+	resources, pages, err := c.listResources(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list resources: %w", err)
 	}
 
-	fmt.Printf("Available resources (%d):\n", len(resources.Resources))
-	for _, resource := range resources.Resources {
+	fmt.Printf("Available resources (%d, %d page(s)):\n", len(resources), pages)
+	for _, resource := range resources {
 		fmt.Printf("  - %s: %s\n", resource.URI, resource.Description)
 	}
+
+	templates, pages, err := c.listResourceTemplates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list resource templates: %w", err)
+	}
+	fmt.Printf("Available resource templates (%d, %d page(s)):\n", len(templates), pages)
+	for _, template := range templates {
+		fmt.Printf("  - %s: %s\n", template.URITemplate, template.Description)
+	}
+	return nil
+}
+
+// ListPrompts lists all available prompts from the server
+func (c *Client) ListPrompts(ctx context.Context) error {
+	prompts, pages, err := c.listPrompts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	fmt.Printf("Available prompts (%d, %d page(s)):\n", len(prompts), pages)
+	for _, prompt := range prompts {
+		fmt.Printf("  - %s: %s\n", prompt.Name, prompt.Description)
+	}
 	return nil
 }
 
+// ReadResource reads a resource from the server and prints its contents
+// the way CallTool prints embedded resources, writing blobs to -output-dir
+// if it is set.
+func (c *Client) ReadResource(ctx context.Context, uri string) error {
+	result, err := c.session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		return fmt.Errorf("failed to read resource %s: %w", uri, err)
+	}
+
+	fmt.Printf("Resource %s returned %d item(s):\n", uri, len(result.Contents))
+	content := make([]mcp.Content, len(result.Contents))
+	for i, contents := range result.Contents {
+		content[i] = &mcp.EmbeddedResource{Resource: contents}
+	}
+	return renderContent(os.Stdout, content, c.config.OutputDir)
+}
+
 // GetServerInfo gets information about the server
 func (c *Client) GetServerInfo(ctx context.Context) error {
 	protocolVersion := c.session.InitializeResult().ProtocolVersion
@@ -190,15 +498,21 @@ func (c *Client) GetServerInfo(ctx context.Context) error {
 	fmt.Printf("  Protocol Version: %s\n", protocolVersion)
 
 	// Try to get basic server capabilities by listing tools
-	tools, err := c.session.ListTools(ctx, &mcp.ListToolsParams{})
+	tools, _, err := c.listTools(ctx)
 	if err == nil {
-		fmt.Printf("  Tools Available: %d\n", len(tools.Tools))
+		fmt.Printf("  Tools Available: %d\n", len(tools))
 	}
 
 	// Try to get resources
-	resources, err := c.session.ListResources(ctx, &mcp.ListResourcesParams{})
+	resources, _, err := c.listResources(ctx)
+	if err == nil {
+		fmt.Printf("  Resources Available: %d\n", len(resources))
+	}
+
+	// Try to get prompts
+	prompts, _, err := c.listPrompts(ctx)
 	if err == nil {
-		fmt.Printf("  Resources Available: %d\n", len(resources.Resources))
+		fmt.Printf("  Prompts Available: %d\n", len(prompts))
 	}
 
 	return nil
@@ -210,23 +524,65 @@ func parseConfig() Config {
 		Transport: transportStdio,
 		Address:   defaultAddress,
 		Port:      8080,
+		SSEPath:   defaultSSEPath,
+		MCPPath:   defaultMCPPath,
+		WSPath:    defaultWSPath,
 		Timeout:   30 * time.Second,
 	}
 
 	var toolName string
 	var toolArgs string
 	var action string
+	var resourceURI string
+	var promptName string
+	var completeArg string
+	var completeValue string
 
-	flag.StringVar(&config.Transport, "transport", config.Transport, "Transport type: stdio, sse, or streamable-http")
+	flag.StringVar(&config.Transport, "transport", config.Transport, "Transport type: stdio, sse, streamable-http, or websocket")
 	flag.StringVar(&config.Address, "address", config.Address, "Server address (for HTTP-based transports)")
 	flag.IntVar(&config.Port, "port", config.Port, "Server port (for HTTP-based transports)")
+	flag.StringVar(&config.URL, "url", "",
+		"Full server endpoint URL for HTTP-based transports, overriding -address, -port and the paths")
+	flag.StringVar(&config.SSEPath, "sse-path", config.SSEPath, "Endpoint path (with any base path) for the sse transport")
+	flag.StringVar(&config.MCPPath, "mcp-path", config.MCPPath,
+		"Endpoint path (with any base path) for the streamable-http transport")
+	flag.StringVar(&config.WSPath, "ws-path", config.WSPath, "Endpoint path (with any base path) for the websocket transport")
 	flag.StringVar(&config.Command, "command", "", "Command to run for stdio transport")
 	flag.DurationVar(&config.Timeout, "timeout", config.Timeout, "Connection timeout")
-	flag.StringVar(&action, "action", "info", "Action to perform: info, list-tools, list-resources, call-tool")
-	flag.StringVar(&toolName, "tool", "", "Tool name to call (for call-tool action)")
-	flag.StringVar(&toolArgs, "args", "{}", "Tool arguments as JSON (for call-tool action)")
+	flag.StringVar(&action, "action", "info",
+		"Action to perform: info, list-tools, list-resources, list-prompts, read-resource, watch-resource, "+
+			"watch-lists, get-prompt, complete, call-tool, cancel-tool")
+	flag.StringVar(&toolName, "tool", "", "Tool name to call (for call-tool and cancel-tool actions)")
+	flag.StringVar(&promptName, "prompt", "", "Prompt name to get or complete (for get-prompt and complete actions)")
+	flag.StringVar(&toolArgs, "args", "{}", "Tool or prompt arguments as JSON (for call-tool, cancel-tool and "+
+		"get-prompt actions), or the arguments already chosen (for complete action)")
+	flag.StringVar(&resourceURI, "uri", "", "Resource URI to read or watch, or URI template to complete (for "+
+		"read-resource, watch-resource and complete actions)")
+	flag.StringVar(&completeArg, "arg", "", "Name of the argument to complete (for complete action)")
+	flag.StringVar(&completeValue, "value", "", "Partial value to complete (for complete action)")
+	flag.BoolVar(&config.Progress, "progress", false,
+		"Request progress notifications for call-tool and print them as they arrive")
+	flag.BoolVar(&config.Annotations, "annotations", false,
+		"Print each tool's title, annotations, icons and _meta (for list-tools action)")
+	flag.DurationVar(&config.CancelAfter, "cancel-after", time.Second,
+		"How long cancel-tool lets the call run before cancelling it")
+	flag.StringVar(&config.LogLevel, "log-level", "",
+		"Minimum level of server log messages to request and print (debug, info, notice, warning, error, "+
+			"critical, alert, emergency)")
+	flag.StringVar(&config.Sampling, "sampling", "",
+		"Answer the server's sampling requests with the last message's text (echo) or that text reversed (reverse)")
+	flag.StringVar(&config.Elicitation, "elicitation", "",
+		"Answer the server's elicitation requests with an action (accept, decline or cancel), or with the "+
+			"scripted responses in a JSON file given as @path")
+	flag.Var((*rootsFlag)(&config.Roots), "root",
+		"Root to advertise, as [name=]uri or [name=]path (repeatable)")
+	flag.Var((*rootsFlag)(&config.ChangeRoots), "change-root",
+		"Root to change to after calling the tool, before calling it again (repeatable, for call-tool action)")
+	flag.StringVar(&config.OutputDir, "output-dir", "",
+		"Directory to write binary content (images, audio, blobs) to (for call-tool and read-resource actions)")
 
 	flag.Parse()
+	config.WatchLists = action == "watch-lists"
 
 	// Parse remaining args as command args for stdio transport
 	if config.Command != "" {
@@ -245,14 +601,63 @@ func parseConfig() Config {
 			config.Port = intValue
 		}
 	}
+	if u, ok := os.LookupEnv("MCP_URL"); ok {
+		config.URL = u
+	}
+	if p, ok := os.LookupEnv("SSE_PATH"); ok {
+		config.SSEPath = p
+	}
+	if p, ok := os.LookupEnv("MCP_PATH"); ok {
+		config.MCPPath = p
+	}
+	if p, ok := os.LookupEnv("WS_PATH"); ok {
+		config.WSPath = p
+	}
 	if c, ok := os.LookupEnv("COMMAND"); ok {
 		config.Command = c
 	}
+	if p, ok := os.LookupEnv("PROGRESS"); ok {
+		if boolValue, err := strconv.ParseBool(p); err == nil {
+			config.Progress = boolValue
+		}
+	}
+	if a, ok := os.LookupEnv("ANNOTATIONS"); ok {
+		if boolValue, err := strconv.ParseBool(a); err == nil {
+			config.Annotations = boolValue
+		}
+	}
+	if d, ok := os.LookupEnv("CANCEL_AFTER"); ok {
+		if duration, err := time.ParseDuration(d); err == nil {
+			config.CancelAfter = duration
+		}
+	}
+	if l, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		config.LogLevel = l
+	}
+	if d, ok := os.LookupEnv("OUTPUT_DIR"); ok {
+		config.OutputDir = d
+	}
+	if s, ok := os.LookupEnv("SAMPLING"); ok {
+		config.Sampling = s
+	}
+	if e, ok := os.LookupEnv("ELICITATION"); ok {
+		config.Elicitation = e
+	}
+	if r, ok := os.LookupEnv("ROOTS"); ok {
+		var roots rootsFlag
+		if err := roots.setAll(r); err == nil {
+			config.Roots = roots
+		}
+	}
 
 	// Store action and tool info in a way we can access them
 	_ = os.Setenv("CLIENT_ACTION", action)
 	_ = os.Setenv("CLIENT_TOOL_NAME", toolName)
 	_ = os.Setenv("CLIENT_TOOL_ARGS", toolArgs)
+	_ = os.Setenv("CLIENT_RESOURCE_URI", resourceURI)
+	_ = os.Setenv("CLIENT_PROMPT_NAME", promptName)
+	_ = os.Setenv("CLIENT_COMPLETE_ARG", completeArg)
+	_ = os.Setenv("CLIENT_COMPLETE_VALUE", completeValue)
 
 	return config
 }
@@ -293,9 +698,70 @@ func main() {
 			log.Fatalf("Failed to list resources: %v", err)
 		}
 
-	case "call-tool":
+	case "list-prompts":
+		if err := client.ListPrompts(ctx); err != nil {
+			log.Fatalf("Failed to list prompts: %v", err)
+		}
+
+	case "read-resource", "watch-resource":
+		resourceURI := os.Getenv("CLIENT_RESOURCE_URI")
+		if resourceURI == "" {
+			log.Fatalf("Resource URI is required for %s action", action)
+		}
+		if action == "watch-resource" {
+			if err := client.WatchResource(ctx, resourceURI); err != nil {
+				log.Fatalf("Failed to watch resource: %v", err)
+			}
+			break
+		}
+		if err := client.ReadResource(ctx, resourceURI); err != nil {
+			log.Fatalf("Failed to read resource: %v", err)
+		}
+
+	case "watch-lists":
+		if err := client.WatchLists(ctx); err != nil {
+			log.Fatalf("Failed to watch lists: %v", err)
+		}
+
+	case "get-prompt":
+		promptName := os.Getenv("CLIENT_PROMPT_NAME")
+		if promptName == "" {
+			log.Fatalf("Prompt name is required for %s action", action)
+		}
+
+		var arguments map[string]interface{}
+		if err := json.Unmarshal([]byte(toolArgs), &arguments); err != nil {
+			log.Fatalf("Failed to parse prompt arguments: %v", err)
+		}
+		if err := client.GetPrompt(ctx, promptName, arguments); err != nil {
+			log.Fatalf("Failed to get prompt: %v", err)
+		}
+
+	case "complete":
+		ref, err := completionRef(os.Getenv("CLIENT_PROMPT_NAME"), os.Getenv("CLIENT_RESOURCE_URI"))
+		if err != nil {
+			log.Fatalf("Invalid completion reference: %v", err)
+		}
+		completeArg := os.Getenv("CLIENT_COMPLETE_ARG")
+		if completeArg == "" {
+			log.Fatalf("Argument name is required for %s action", action)
+		}
+
+		var arguments map[string]interface{}
+		if err := json.Unmarshal([]byte(toolArgs), &arguments); err != nil {
+			log.Fatalf("Failed to parse completion context: %v", err)
+		}
+		resolved, err := promptArguments(arguments)
+		if err != nil {
+			log.Fatalf("Failed to parse completion context: %v", err)
+		}
+		if err := client.Complete(ctx, ref, completeArg, os.Getenv("CLIENT_COMPLETE_VALUE"), resolved); err != nil {
+			log.Fatalf("Failed to complete: %v", err)
+		}
+
+	case "call-tool", "cancel-tool":
 		if toolName == "" {
-			log.Fatal("Tool name is required for call-tool action")
+			log.Fatalf("Tool name is required for %s action", action)
 		}
 
 		var arguments map[string]interface{}
@@ -303,9 +769,21 @@ func main() {
 			log.Fatalf("Failed to parse tool arguments: %v", err)
 		}
 
+		if action == "cancel-tool" {
+			if err := client.CancelTool(ctx, toolName, arguments, config.CancelAfter); err != nil {
+				log.Fatalf("Failed to call tool: %v", err)
+			}
+			break
+		}
 		if err := client.CallTool(ctx, toolName, arguments); err != nil {
 			log.Fatalf("Failed to call tool: %v", err)
 		}
+		if len(config.ChangeRoots) > 0 {
+			client.ChangeRoots(config.ChangeRoots)
+			if err := client.CallTool(ctx, toolName, arguments); err != nil {
+				log.Fatalf("Failed to call tool: %v", err)
+			}
+		}
 
 	default:
 		log.Fatalf("Unknown action: %s", strconv.Quote(action))
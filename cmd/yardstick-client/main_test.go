@@ -3,18 +3,23 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
 )
 
 func TestNewClient(t *testing.T) {
@@ -152,6 +157,240 @@ func TestClient_ConnectStreamableHTTP(t *testing.T) {
 	assert.NotNil(t, transport)
 }
 
+func TestClient_IntegrationWebSocket(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	mux := http.NewServeMux()
+	mux.Handle("/ws", wsconn.NewHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(mockServer.URL, "http://"))
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(Config{Transport: "websocket", Address: host, Port: port})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	assert.NoError(t, client.GetServerInfo(ctx))
+	assert.NoError(t, client.ListTools(ctx))
+}
+
+func TestClient_Endpoint(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		path   string
+		def    string
+		want   string
+	}{
+		{
+			name:   "default path",
+			config: Config{Address: "localhost", Port: 8080},
+			def:    defaultSSEPath,
+			want:   "http://localhost:8080/sse",
+		},
+		{
+			name:   "custom path with base prefix",
+			config: Config{Address: "gateway", Port: 443},
+			path:   "/tenant-a/mcp",
+			def:    defaultMCPPath,
+			want:   "http://gateway:443/tenant-a/mcp",
+		},
+		{
+			name:   "ipv6 address",
+			config: Config{Address: "::1", Port: 8080},
+			def:    defaultMCPPath,
+			want:   "http://[::1]:8080/mcp",
+		},
+		{
+			name:   "url overrides address, port and path",
+			config: Config{Address: "localhost", Port: 8080, URL: "https://example.com/prefix/mcp"},
+			path:   "/mcp",
+			def:    defaultMCPPath,
+			want:   "https://example.com/prefix/mcp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewClient(tt.config).endpoint(tt.path, tt.def))
+		})
+	}
+}
+
+// TestClient_IntegrationBasePath connects to servers mounted under a path
+// prefix, once through -sse-path and once through -url.
+func TestClient_IntegrationBasePath(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	getServer := func(_ *http.Request) *mcp.Server { return server }
+	mux := http.NewServeMux()
+	mux.Handle("/prefix/events", mcp.NewSSEHandler(getServer, nil))
+	mux.Handle("/prefix/rpc", mcp.NewStreamableHTTPHandler(getServer, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(mockServer.URL, "http://"))
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	configs := map[string]Config{
+		"sse path":       {Transport: "sse", Address: host, Port: port, SSEPath: "/prefix/events"},
+		"streamable url": {Transport: "streamable-http", URL: mockServer.URL + "/prefix/rpc"},
+	}
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client := NewClient(config)
+			require.NoError(t, client.Connect(ctx))
+			defer client.Close()
+			assert.NoError(t, client.ListTools(ctx))
+		})
+	}
+}
+
+func TestClient_IntegrationProgress(t *testing.T) {
+	tokens := make(chan any, 1)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "slow"}, func(
+		ctx context.Context, req *mcp.CallToolRequest, _ map[string]any,
+	) (*mcp.CallToolResult, map[string]any, error) {
+		tokens <- req.Params.GetProgressToken()
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: req.Params.GetProgressToken(), Progress: 1, Total: 2,
+		})
+		return nil, map[string]any{}, err
+	})
+	mockServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(Config{Transport: "streamable-http", URL: mockServer.URL, Progress: true})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+	require.NoError(t, client.CallTool(ctx, "slow", map[string]any{}))
+	assert.Equal(t, progressToken, <-tokens)
+}
+
+func TestClient_CancelTool(t *testing.T) {
+	cancelled := make(chan error, 1)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "block"}, func(
+		ctx context.Context, _ *mcp.CallToolRequest, _ map[string]any,
+	) (*mcp.CallToolResult, map[string]any, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return nil, nil, ctx.Err()
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "quick"}, func(
+		_ context.Context, _ *mcp.CallToolRequest, _ map[string]any,
+	) (*mcp.CallToolResult, map[string]any, error) {
+		return nil, map[string]any{}, nil
+	})
+	mockServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := NewClient(Config{Transport: "streamable-http", URL: mockServer.URL})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	require.NoError(t, client.CancelTool(ctx, "block", map[string]any{}, 50*time.Millisecond))
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server-side call was not cancelled")
+	}
+
+	assert.NoError(t, client.CancelTool(ctx, "quick", map[string]any{}, time.Second))
+	assert.Error(t, client.CancelTool(ctx, "missing", map[string]any{}, time.Second))
+}
+
+// TestClient_IntegrationLogLevel checks -log-level reaches the server both
+// ways: with logging/setLevel on a session that negotiates an older
+// protocol, and in each call's _meta on a modern one.
+func TestClient_IntegrationLogLevel(t *testing.T) {
+	levels := make(chan any, 2)
+	tokens := make(chan any, 1)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if params, ok := req.GetParams().(*mcp.SetLoggingLevelParams); ok && method == "logging/setLevel" {
+				levels <- string(params.Level)
+			}
+			return next(ctx, method, req)
+		}
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "log"}, func(
+		ctx context.Context, req *mcp.CallToolRequest, _ map[string]any,
+	) (*mcp.CallToolResult, map[string]any, error) {
+		if level, ok := req.Params.Meta[mcp.MetaKeyLogLevel]; ok {
+			levels <- level
+		}
+		tokens <- req.Params.GetProgressToken()
+		err := req.Session.Log(ctx, &mcp.LoggingMessageParams{Level: "error", Data: "boom"})
+		return nil, map[string]any{}, err
+	})
+	getServer := func(_ *http.Request) *mcp.Server { return server }
+	mux := http.NewServeMux()
+	mux.Handle("/sse", mcp.NewSSEHandler(getServer, nil))
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(getServer, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	tests := []struct {
+		transport string
+		modern    bool
+	}{
+		{transport: "streamable-http", modern: false},
+		{transport: "sse", modern: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.transport, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			path := map[string]string{"sse": "/sse", "streamable-http": "/mcp"}[tt.transport]
+			client := NewClient(Config{Transport: tt.transport, URL: mockServer.URL + path, LogLevel: "warning",
+				Progress: true})
+			require.NoError(t, client.Connect(ctx))
+			defer client.Close()
+			require.Equal(t, tt.modern, client.modern())
+
+			require.NoError(t, client.CallTool(ctx, "log", map[string]any{}))
+			assert.Equal(t, "warning", <-levels)
+			assert.Empty(t, levels)
+			// The level must not displace the progress token in _meta.
+			assert.Equal(t, progressToken, <-tokens)
+		})
+	}
+}
+
+func TestFormatLogMessage(t *testing.T) {
+	assert.Equal(t, "[log] error yardstick: boom",
+		formatLogMessage(&mcp.LoggingMessageParams{Level: "error", Logger: "yardstick", Data: "boom"}))
+	assert.Equal(t, `[log] info: {"n":1}`,
+		formatLogMessage(&mcp.LoggingMessageParams{Level: "info", Data: map[string]any{"n": 1}}))
+}
+
+func TestFormatProgress(t *testing.T) {
+	assert.Equal(t, "[progress] 2/5 step 2 of 5",
+		formatProgress(&mcp.ProgressNotificationParams{Progress: 2, Total: 5, Message: "step 2 of 5"}))
+	assert.Equal(t, "[progress] 0.5", formatProgress(&mcp.ProgressNotificationParams{Progress: 0.5}))
+}
+
 func TestClient_Connect_UnsupportedTransport(t *testing.T) {
 	client := NewClient(Config{
 		Transport: "unsupported",
@@ -392,6 +631,84 @@ func TestClient_ListResources(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestClient_ReadResource(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: "test://blob/{name}", Name: "blob"},
+		func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{
+				{URI: req.Params.URI, MIMEType: "image/png", Blob: []byte("not really a png")},
+			}}, nil
+		})
+	mockServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	client := NewClient(Config{Transport: "streamable-http", URL: mockServer.URL, OutputDir: dir})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	require.NoError(t, client.ListResources(ctx))
+	require.NoError(t, client.ReadResource(ctx, "test://blob/a"))
+	data, err := os.ReadFile(filepath.Join(dir, "content-0.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "not really a png", string(data))
+
+	assert.ErrorContains(t, client.ReadResource(ctx, "test://missing"), "not found")
+}
+
+// TestClient_WatchResource watches a resource that keeps changing over a
+// session that subscribes with resources/subscribe and one that uses
+// subscriptions/listen, and checks each update is followed by a re-read.
+func TestClient_WatchResource(t *testing.T) {
+	var reads atomic.Int64
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	server.AddResource(&mcp.Resource{URI: "test://live", Name: "live", MIMEType: "text/plain"},
+		func(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			reads.Add(1)
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "changed"}}}, nil
+		})
+	getServer := func(_ *http.Request) *mcp.Server { return server }
+	mux := http.NewServeMux()
+	mux.Handle("/sse", mcp.NewSSEHandler(getServer, nil))
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(getServer, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	for _, transport := range []string{"streamable-http", "sse"} {
+		t.Run(transport, func(t *testing.T) {
+			reads.Store(0)
+			path := map[string]string{"sse": "/sse", "streamable-http": "/mcp"}[transport]
+			client := NewClient(Config{Transport: transport, URL: mockServer.URL + path})
+			require.NoError(t, client.Connect(context.Background()))
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			go func() {
+				ticker := time.NewTicker(20 * time.Millisecond)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						_ = server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: "test://live"})
+					}
+				}
+			}()
+			require.NoError(t, client.WatchResource(ctx, "test://live"))
+			assert.Positive(t, client.updates.Load())
+			assert.Positive(t, reads.Load())
+		})
+	}
+}
+
 func TestClient_Close(t *testing.T) {
 	client := NewClient(Config{})
 
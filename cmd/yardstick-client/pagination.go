@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxListPages bounds how many pages a listing follows, so a server or
+// gateway that keeps handing out fresh cursors can't keep the client
+// listing forever.
+const maxListPages = 10000
+
+// listAll fetches every page of a listing. fetch gets each page's cursor,
+// empty for the first, and returns the next one; the listing ends when it
+// returns none. It returns the number of pages fetched. A cursor that
+// comes back twice is an error rather than a loop.
+func listAll(fetch func(cursor string) (string, error)) (int, error) {
+	seen := map[string]bool{}
+	cursor := ""
+	for pages := 1; ; pages++ {
+		next, err := fetch(cursor)
+		if err != nil {
+			if pages > 1 {
+				return pages, fmt.Errorf("page %d (cursor %q): %w", pages, cursor, err)
+			}
+			return pages, err
+		}
+		if next == "" {
+			return pages, nil
+		}
+		if seen[next] {
+			return pages, fmt.Errorf("page %d returned cursor %q, which an earlier page already returned", pages, next)
+		}
+		if pages == maxListPages {
+			return pages, fmt.Errorf("still paging after %d pages", maxListPages)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+// listTools returns every tool the server offers and how many pages they
+// took.
+func (c *Client) listTools(ctx context.Context) ([]*mcp.Tool, int, error) {
+	var tools []*mcp.Tool
+	pages, err := listAll(func(cursor string) (string, error) {
+		res, err := c.session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		if err != nil {
+			return "", err
+		}
+		tools = append(tools, res.Tools...)
+		return res.NextCursor, nil
+	})
+	return tools, pages, err
+}
+
+// listResources returns every resource the server offers and how many
+// pages they took.
+func (c *Client) listResources(ctx context.Context) ([]*mcp.Resource, int, error) {
+	var resources []*mcp.Resource
+	pages, err := listAll(func(cursor string) (string, error) {
+		res, err := c.session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+		if err != nil {
+			return "", err
+		}
+		resources = append(resources, res.Resources...)
+		return res.NextCursor, nil
+	})
+	return resources, pages, err
+}
+
+// listResourceTemplates returns every resource template the server offers
+// and how many pages they took.
+func (c *Client) listResourceTemplates(ctx context.Context) ([]*mcp.ResourceTemplate, int, error) {
+	var templates []*mcp.ResourceTemplate
+	pages, err := listAll(func(cursor string) (string, error) {
+		res, err := c.session.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{Cursor: cursor})
+		if err != nil {
+			return "", err
+		}
+		templates = append(templates, res.ResourceTemplates...)
+		return res.NextCursor, nil
+	})
+	return templates, pages, err
+}
+
+// listPrompts returns every prompt the server offers and how many pages
+// they took.
+func (c *Client) listPrompts(ctx context.Context) ([]*mcp.Prompt, int, error) {
+	var prompts []*mcp.Prompt
+	pages, err := listAll(func(cursor string) (string, error) {
+		res, err := c.session.ListPrompts(ctx, &mcp.ListPromptsParams{Cursor: cursor})
+		if err != nil {
+			return "", err
+		}
+		prompts = append(prompts, res.Prompts...)
+		return res.NextCursor, nil
+	})
+	return prompts, pages, err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAll(t *testing.T) {
+	var cursors []string
+	pages, err := listAll(func(cursor string) (string, error) {
+		cursors = append(cursors, cursor)
+		if len(cursors) == 3 {
+			return "", nil
+		}
+		return strconv.Itoa(len(cursors)), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"", "1", "2"}, cursors)
+
+	pages, err = listAll(func(cursor string) (string, error) {
+		if cursor == "" {
+			return "next", nil
+		}
+		return "", errors.New("boom")
+	})
+	assert.Equal(t, 2, pages)
+	assert.EqualError(t, err, `page 2 (cursor "next"): boom`)
+
+	_, err = listAll(func(string) (string, error) { return "same", nil })
+	assert.ErrorContains(t, err, "already returned")
+
+	pages, err = listAll(func(cursor string) (string, error) { return cursor + "x", nil })
+	assert.Equal(t, maxListPages, pages)
+	assert.ErrorContains(t, err, "still paging")
+}
+
+func TestClient_ListPaginated(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{PageSize: 2})
+	for i := range 5 {
+		name := fmt.Sprintf("tool%d", i)
+		mcp.AddTool(server, &mcp.Tool{Name: name}, func(
+			context.Context, *mcp.CallToolRequest, map[string]any,
+		) (*mcp.CallToolResult, map[string]any, error) {
+			return nil, map[string]any{}, nil
+		})
+		server.AddResource(&mcp.Resource{URI: "test://" + name, Name: name},
+			func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) { return nil, nil })
+	}
+	mockServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := NewClient(Config{Transport: "streamable-http", URL: mockServer.URL})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	tools, pages, err := client.listTools(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tools, 5)
+	assert.Equal(t, 3, pages)
+
+	resources, pages, err := client.listResources(ctx)
+	require.NoError(t, err)
+	assert.Len(t, resources, 5)
+	assert.Equal(t, 3, pages)
+
+	templates, pages, err := client.listResourceTemplates(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+	assert.Equal(t, 1, pages)
+
+	require.NoError(t, client.ListTools(ctx))
+	require.NoError(t, client.ListResources(ctx))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// promptArguments converts -args to prompt arguments, which are all
+// strings. Other JSON values are passed as their JSON text, so
+// {"turns":3} sends "3".
+func promptArguments(arguments map[string]any) (map[string]string, error) {
+	args := make(map[string]string, len(arguments))
+	for name, value := range arguments {
+		if s, ok := value.(string); ok {
+			args[name] = s
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode argument %q: %w", name, err)
+		}
+		args[name] = string(raw)
+	}
+	return args, nil
+}
+
+// GetPrompt gets a prompt from the server and prints each message's role
+// and content the way CallTool prints tool results, writing binary content
+// to -output-dir if it is set, numbered by message.
+func (c *Client) GetPrompt(ctx context.Context, name string, arguments map[string]any) error {
+	args, err := promptArguments(arguments)
+	if err != nil {
+		return err
+	}
+	result, err := c.session.GetPrompt(ctx, &mcp.GetPromptParams{Name: name, Arguments: args})
+	if err != nil {
+		return fmt.Errorf("failed to get prompt %s: %w", name, err)
+	}
+
+	fmt.Printf("Prompt %s returned %d message(s):\n", name, len(result.Messages))
+	if result.Description != "" {
+		fmt.Printf("Description: %s\n", result.Description)
+	}
+	for i, message := range result.Messages {
+		fmt.Printf("[%s]\n", message.Role)
+		if err := renderItem(os.Stdout, i, message.Content, c.config.OutputDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptArguments(t *testing.T) {
+	args, err := promptArguments(map[string]any{
+		"text": "hello", "turns": float64(3), "flag": true, "list": []any{"a", 1.5}, "none": nil,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"text": "hello", "turns": "3", "flag": "true", "list": `["a",1.5]`, "none": "null",
+	}, args)
+
+	args, err = promptArguments(nil)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func TestClient_GetPrompt(t *testing.T) {
+	var got map[string]string
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddPrompt(&mcp.Prompt{Name: "greet", Arguments: []*mcp.PromptArgument{{Name: "name", Required: true}}},
+		func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			got = req.Params.Arguments
+			return &mcp.GetPromptResult{Description: "A greeting", Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: "Greet " + req.Params.Arguments["name"]}},
+				{Role: "assistant", Content: &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+					URI: "test://card.png", MIMEType: "image/png", Blob: []byte("not really a png"),
+				}}},
+			}}, nil
+		})
+	mockServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	client := NewClient(Config{Transport: "streamable-http", URL: mockServer.URL, OutputDir: dir})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	require.NoError(t, client.ListPrompts(ctx))
+	require.NoError(t, client.GetPrompt(ctx, "greet", map[string]any{"name": "yardstick", "times": 2.0}))
+	assert.Equal(t, map[string]string{"name": "yardstick", "times": "2"}, got)
+	// Binary content is numbered by message.
+	data, err := os.ReadFile(filepath.Join(dir, "content-1.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "not really a png", string(data))
+
+	assert.ErrorContains(t, client.GetPrompt(ctx, "missing", nil), `unknown prompt "missing"`)
+}
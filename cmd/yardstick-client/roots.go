@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// rootsFlag collects the values of a repeatable root flag.
+type rootsFlag []*mcp.Root
+
+func (f *rootsFlag) String() string {
+	var uris []string
+	for _, root := range *f {
+		uris = append(uris, root.URI)
+	}
+	return strings.Join(uris, ",")
+}
+
+func (f *rootsFlag) Set(value string) error {
+	root, err := parseRoot(value)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, root)
+	return nil
+}
+
+// setAll sets the roots in a comma-separated list, as in ROOTS.
+func (f *rootsFlag) setAll(list string) error {
+	for _, value := range strings.Split(list, ",") {
+		if err := f.Set(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRoot parses a root given as [name=]uri-or-path. A path, anything
+// without "://", is made absolute and turned into a file:// URI. The name
+// can't contain ':' or '/', so a URI with '=' in its query is left whole.
+func parseRoot(value string) (*mcp.Root, error) {
+	root := &mcp.Root{}
+	location := value
+	if name, rest, ok := strings.Cut(value, "="); ok && !strings.ContainsAny(name, ":/") {
+		root.Name, location = name, rest
+	}
+	if location == "" {
+		return nil, fmt.Errorf("root %q has no URI or path", value)
+	}
+	if strings.Contains(location, "://") {
+		root.URI = location
+		return root, nil
+	}
+	path, err := filepath.Abs(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root path %q: %w", location, err)
+	}
+	root.URI = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	return root, nil
+}
+
+// ChangeRoots replaces the client's roots with roots. The SDK sends the
+// server a notifications/roots/list_changed for the roots removed and
+// another for those added, leaving out either if there are none.
+func (c *Client) ChangeRoots(roots []*mcp.Root) {
+	same := func(a *mcp.Root) func(*mcp.Root) bool {
+		return func(b *mcp.Root) bool { return a.URI == b.URI && a.Name == b.Name }
+	}
+	var removed []string
+	for _, root := range c.roots {
+		if !slices.ContainsFunc(roots, same(root)) {
+			removed = append(removed, root.URI)
+		}
+	}
+	var added []*mcp.Root
+	for _, root := range roots {
+		if !slices.ContainsFunc(c.roots, same(root)) {
+			added = append(added, root)
+		}
+	}
+	c.client.RemoveRoots(removed...)
+	c.client.AddRoots(added...)
+	c.roots = roots
+	fmt.Printf("[roots] changed to %s\n", (*rootsFlag)(&c.roots))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoot(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		value string
+		want  *mcp.Root
+	}{
+		{"file:///work", &mcp.Root{URI: "file:///work"}},
+		{"work=file:///work", &mcp.Root{URI: "file:///work", Name: "work"}},
+		{"https://example.com/a?b=c", &mcp.Root{URI: "https://example.com/a?b=c"}},
+		{dir, &mcp.Root{URI: "file://" + filepath.ToSlash(dir)}},
+		{"tmp=" + dir, &mcp.Root{URI: "file://" + filepath.ToSlash(dir), Name: "tmp"}},
+	}
+	for _, tt := range tests {
+		root, err := parseRoot(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, root, tt.value)
+	}
+
+	_, err := parseRoot("work=")
+	assert.ErrorContains(t, err, `root "work=" has no URI or path`)
+
+	var roots rootsFlag
+	require.NoError(t, roots.setAll("a=file:///a,file:///b"))
+	assert.Equal(t, rootsFlag{{URI: "file:///a", Name: "a"}, {URI: "file:///b"}}, roots)
+	assert.Equal(t, "file:///a,file:///b", roots.String())
+}
+
+// TestClient_Roots lists the client's roots, changes them and lists them
+// again, over streamable HTTP, where the server sends roots/list, and SSE,
+// where the request comes back as an input request in the tool's result.
+func TestClient_Roots(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "list_roots", InputSchema: &jsonschema.Schema{Type: "object"}},
+		func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			answer, ok := req.Params.InputResponses["roots"].(*mcp.ListRootsResult)
+			if !ok {
+				return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{"roots": &mcp.ListRootsParams{}}}, nil
+			}
+			var uris []string
+			for _, root := range answer.Roots {
+				uris = append(uris, root.Name+"="+root.URI)
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: strings.Join(uris, ",")}}}, nil
+		})
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mux.Handle("/sse", mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	for _, transport := range []string{transportStreamableHTTP, transportSSE} {
+		t.Run(transport, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			path := map[string]string{transportStreamableHTTP: "/mcp", transportSSE: "/sse"}[transport]
+			client := NewClient(Config{
+				Transport: transport, URL: mockServer.URL + path,
+				Roots: []*mcp.Root{{URI: "file:///a", Name: "a"}, {URI: "file:///b"}},
+			})
+			require.NoError(t, client.Connect(ctx))
+			defer client.Close()
+
+			listRoots := func() string {
+				t.Helper()
+				result, err := client.session.CallTool(ctx, &mcp.CallToolParams{Name: "list_roots", Arguments: map[string]any{}})
+				require.NoError(t, err)
+				require.False(t, result.IsError, "%v", result.Content)
+				require.Len(t, result.Content, 1)
+				return result.Content[0].(*mcp.TextContent).Text
+			}
+			assert.Equal(t, "a=file:///a,=file:///b", listRoots())
+
+			client.ChangeRoots([]*mcp.Root{{URI: "file:///b"}, {URI: "file:///c", Name: "c"}})
+			assert.Equal(t, "=file:///b,c=file:///c", listRoots())
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	samplingEcho    = "echo"
+	samplingReverse = "reverse"
+)
+
+// samplingHandler returns a CreateMessageHandler that answers without a
+// model, deterministically: echo answers with the text of the last
+// message, reverse with that text reversed. Each request is printed as it
+// is answered.
+func samplingHandler(mode string) (func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error), error) {
+	var answer func(string) string
+	switch mode {
+	case samplingEcho:
+		answer = func(s string) string { return s }
+	case samplingReverse:
+		answer = reverse
+	default:
+		return nil, fmt.Errorf("unknown sampling mode %q: valid values are %s, %s", mode, samplingEcho, samplingReverse)
+	}
+	return func(_ context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		messages := req.Params.Messages
+		if len(messages) == 0 {
+			return nil, fmt.Errorf("sampling request has no messages")
+		}
+		text, ok := messages[len(messages)-1].Content.(*mcp.TextContent)
+		if !ok {
+			return nil, fmt.Errorf("can only sample text, not %T", messages[len(messages)-1].Content)
+		}
+		result := answer(text.Text)
+		fmt.Printf("[sampling] %s %q -> %q\n", mode, text.Text, result)
+		return &mcp.CreateMessageResult{
+			Model:      "yardstick-" + mode,
+			Role:       "assistant",
+			StopReason: "endTurn",
+			Content:    &mcp.TextContent{Text: result},
+		}, nil
+	}, nil
+}
+
+// reverse reverses s by rune.
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
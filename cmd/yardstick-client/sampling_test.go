@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	request := func(messages ...*mcp.SamplingMessage) *mcp.CreateMessageRequest {
+		return &mcp.CreateMessageRequest{Params: &mcp.CreateMessageParams{Messages: messages}}
+	}
+	text := func(role mcp.Role, s string) *mcp.SamplingMessage {
+		return &mcp.SamplingMessage{Role: role, Content: &mcp.TextContent{Text: s}}
+	}
+
+	tests := []struct {
+		mode string
+		req  *mcp.CreateMessageRequest
+		want string
+	}{
+		{samplingEcho, request(text("user", "héllo")), "héllo"},
+		{samplingReverse, request(text("user", "héllo")), "olléh"},
+		{samplingReverse, request(text("user", "first"), text("assistant", "ok"), text("user", "last")), "tsal"},
+		{samplingReverse, request(text("user", "")), ""},
+	}
+	for _, tt := range tests {
+		handler, err := samplingHandler(tt.mode)
+		require.NoError(t, err)
+		result, err := handler(context.Background(), tt.req)
+		require.NoError(t, err)
+		assert.Equal(t, &mcp.CreateMessageResult{
+			Model: "yardstick-" + tt.mode, Role: "assistant", StopReason: "endTurn", Content: &mcp.TextContent{Text: tt.want},
+		}, result)
+	}
+
+	handler, err := samplingHandler(samplingEcho)
+	require.NoError(t, err)
+	_, err = handler(context.Background(), request())
+	assert.ErrorContains(t, err, "no messages")
+	_, err = handler(context.Background(), request(&mcp.SamplingMessage{Role: "user", Content: &mcp.ImageContent{}}))
+	assert.ErrorContains(t, err, "can only sample text")
+
+	_, err = samplingHandler("upper")
+	assert.ErrorContains(t, err, `unknown sampling mode "upper"`)
+}
+
+// TestClient_Sampling answers a tool's sampling request over streamable
+// HTTP, where the server sends sampling/createMessage, and SSE, where the
+// request comes back as an input request in the tool's result.
+func TestClient_Sampling(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{Name: "sample", InputSchema: &jsonschema.Schema{Type: "object"}},
+		func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			answer, ok := req.Params.InputResponses["sample"].(*mcp.CreateMessageWithToolsResult)
+			if !ok {
+				return &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{"sample": &mcp.CreateMessageParams{
+					Messages: []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "yardstick"}}},
+				}}}, nil
+			}
+			return &mcp.CallToolResult{Content: answer.Content}, nil
+		})
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mux.Handle("/sse", mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server { return server }, nil))
+	mockServer := httptest.NewServer(mux)
+	defer mockServer.Close()
+
+	for _, transport := range []string{transportStreamableHTTP, transportSSE} {
+		t.Run(transport, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			path := map[string]string{transportStreamableHTTP: "/mcp", transportSSE: "/sse"}[transport]
+			client := NewClient(Config{Transport: transport, URL: mockServer.URL + path, Sampling: samplingReverse})
+			require.NoError(t, client.Connect(ctx))
+			defer client.Close()
+
+			result, err := client.session.CallTool(ctx, &mcp.CallToolParams{Name: "sample", Arguments: map[string]any{}})
+			require.NoError(t, err)
+			require.False(t, result.IsError, "%v", result.Content)
+			require.Len(t, result.Content, 1)
+			assert.Equal(t, "kcitsdray", result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+
+	client := NewClient(Config{Transport: transportSSE, URL: mockServer.URL + "/sse", Sampling: "upper"})
+	assert.ErrorContains(t, client.Connect(context.Background()), `unknown sampling mode "upper"`)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// hintsPrefix starts the name of each hints_ tool, which is followed by
+	// a 1 or 0 for each of readOnlyHint, destructiveHint, idempotentHint and
+	// openWorldHint, in that order.
+	hintsPrefix = "hints_"
+
+	// metaKeyYardstick is the _meta key the meta tool sets.
+	metaKeyYardstick = "io.github.stackloklabs/yardstick"
+)
+
+// AnnotatedToolResponse represents the response from the tools that carry
+// annotations, titles, icons or _meta: what the server declared for the
+// tool that was called, so a host that called a different one is caught.
+type AnnotatedToolResponse struct {
+	Tool        string               `json:"tool"`
+	Title       string               `json:"title,omitempty"`
+	Annotations *mcp.ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// hintsTool returns the hints_ tool with the given hints, all set
+// explicitly, and a title spelling them out.
+func hintsTool(readOnly, destructive, idempotent, openWorld bool) *mcp.Tool {
+	bit := func(b bool) string {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+	word := func(b bool, yes, no string) string {
+		if b {
+			return yes
+		}
+		return no
+	}
+	title := strings.Join([]string{
+		word(readOnly, "Read-only", "Writing"),
+		word(destructive, "destructive", "non-destructive"),
+		word(idempotent, "idempotent", "non-idempotent"),
+		word(openWorld, "open-world", "closed-world"),
+	}, ", ")
+	return &mcp.Tool{
+		Name:        hintsPrefix + bit(readOnly) + bit(destructive) + bit(idempotent) + bit(openWorld),
+		Description: "Do nothing, under the annotations: " + strings.ToLower(title) + ".",
+		Annotations: &mcp.ToolAnnotations{
+			Title:           title,
+			ReadOnlyHint:    readOnly,
+			DestructiveHint: &destructive,
+			IdempotentHint:  idempotent,
+			OpenWorldHint:   &openWorld,
+		},
+	}
+}
+
+// svgIcon returns a data URI icon of a square in fill, for a background
+// of the given theme.
+func svgIcon(fill string, theme mcp.IconTheme) mcp.Icon {
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16">`+
+		`<rect x="2" y="2" width="12" height="12" fill="%s"/></svg>`, fill)
+	return mcp.Icon{
+		Source:   "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg)),
+		MIMEType: "image/svg+xml",
+		Sizes:    []string{"any"},
+		Theme:    theme,
+	}
+}
+
+// annotatedTools returns the tools whose definitions are the point: every
+// combination of the four hints, a tool with defaults for all of them, the
+// three ways of giving a title, icons, and _meta.
+func annotatedTools() []*mcp.Tool {
+	var tools []*mcp.Tool
+	for i := range 16 {
+		tools = append(tools, hintsTool(i&8 != 0, i&4 != 0, i&2 != 0, i&1 != 0))
+	}
+	return append(tools,
+		&mcp.Tool{
+			Name: "hints_default",
+			Description: "Do nothing, under annotations that leave every hint at its default: " +
+				"not read-only, destructive, not idempotent, open-world.",
+			Annotations: &mcp.ToolAnnotations{},
+		},
+		&mcp.Tool{
+			Name:        "titled_tool",
+			Title:       "Tool Title",
+			Description: "Do nothing. Has a title, and no annotations.",
+		},
+		&mcp.Tool{
+			Name:        "titled_annotation",
+			Description: "Do nothing. Has a title in its annotations only.",
+			Annotations: &mcp.ToolAnnotations{Title: "Annotation Title", ReadOnlyHint: true},
+		},
+		&mcp.Tool{
+			Name:        "titled_both",
+			Title:       "Tool Title Wins",
+			Description: "Do nothing. Has different titles of its own and in its annotations; its own should be shown.",
+			Annotations: &mcp.ToolAnnotations{Title: "Annotation Title Loses", ReadOnlyHint: true},
+		},
+		&mcp.Tool{
+			Name:        "icons",
+			Title:       "Icons",
+			Description: "Do nothing. Has an icon for light backgrounds and one for dark.",
+			Icons:       []mcp.Icon{svgIcon("#1f2937", mcp.IconThemeLight), svgIcon("#f9fafb", mcp.IconThemeDark)},
+		},
+		&mcp.Tool{
+			Name:        "meta",
+			Description: "Do nothing. Has _meta with a string, a number, a boolean, a list and an object.",
+			Meta: mcp.Meta{metaKeyYardstick: map[string]any{
+				"string": "yardstick", "number": 42, "boolean": true,
+				"list": []any{"a", "b"}, "object": map[string]any{"nested": true},
+			}},
+		},
+	)
+}
+
+// annotatedHandler returns the handler for tool, which reports tool.
+func annotatedHandler(tool *mcp.Tool) mcp.ToolHandlerFor[struct{}, AnnotatedToolResponse] {
+	return func(context.Context, *mcp.CallToolRequest, struct{}) (*mcp.CallToolResult, AnnotatedToolResponse, error) {
+		return nil, AnnotatedToolResponse{Tool: tool.Name, Title: tool.Title, Annotations: tool.Annotations}, nil
+	}
+}
+
+// addAnnotatedTools registers the tools from annotatedTools, for testing
+// how hosts show tools and decide which calls to confirm.
+func addAnnotatedTools(server *mcp.Server) {
+	for _, tool := range annotatedTools() {
+		addTool(server, tool, annotatedHandler(tool))
+	}
+}
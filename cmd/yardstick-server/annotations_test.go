@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHintsTool(t *testing.T) {
+	tool := hintsTool(true, false, true, false)
+	assert.Equal(t, "hints_1010", tool.Name)
+	data, err := json.Marshal(tool.Annotations)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "Read-only, non-destructive, idempotent, closed-world",
+		"readOnlyHint": true, "destructiveHint": false, "idempotentHint": true, "openWorldHint": false
+	}`, string(data))
+
+	names := map[string]bool{}
+	for _, tool := range annotatedTools() {
+		assert.False(t, names[tool.Name], "duplicate tool %s", tool.Name)
+		names[tool.Name] = true
+	}
+	assert.Len(t, names, 22)
+}
+
+// TestAnnotatedTools lists the annotated tools as a client sees them and
+// calls a few.
+func TestAnnotatedTools(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectInMemory(ctx, t, nil, addAnnotatedTools, nil)
+
+	res, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	tools := map[string]*mcp.Tool{}
+	for _, tool := range res.Tools {
+		tools[tool.Name] = tool
+	}
+	require.Len(t, tools, 22)
+
+	data, err := json.Marshal(tools["hints_default"].Annotations)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"readOnlyHint": false, "idempotentHint": false}`, string(data))
+	assert.Equal(t, "Tool Title Wins", tools["titled_both"].Title)
+	assert.Equal(t, "Annotation Title Loses", tools["titled_both"].Annotations.Title)
+	require.Len(t, tools["icons"].Icons, 2)
+	assert.Equal(t, mcp.IconThemeDark, tools["icons"].Icons[1].Theme)
+	assert.Contains(t, tools["icons"].Icons[0].Source, "data:image/svg+xml;base64,")
+	assert.Equal(t, map[string]any{
+		"string": "yardstick", "number": float64(42), "boolean": true,
+		"list": []any{"a", "b"}, "object": map[string]any{"nested": true},
+	}, tools["meta"].Meta[metaKeyYardstick])
+
+	for _, name := range []string{"hints_0101", "titled_tool", "meta"} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: map[string]any{}})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "%v", result.Content)
+		var response AnnotatedToolResponse
+		require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+		assert.Equal(t, name, response.Tool)
+		assert.Equal(t, tools[name].Title, response.Title)
+		assert.Equal(t, tools[name].Annotations, response.Annotations)
+	}
+}
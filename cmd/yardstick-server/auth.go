@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const methodToolsCall = "tools/call"
+
+// toolScopes maps a tool name to the scopes a bearer token must carry to
+// call it. It is populated by addTool from each tool's declared scopes,
+// then overridden per tool by TOOL_SCOPES (see toolScopeOverrides).
+var toolScopes = map[string][]string{}
+
+// addTool registers a tool with mcp.AddTool and records the scopes it
+// requires, so scopeWrapper can reject calls from under-scoped tokens with
+// a 403 before they reach the handler. A tool declaring no scopes can be
+// called by any valid token.
+func addTool[In, Out any](server *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out], scopes ...string) {
+	mcp.AddTool(server, tool, handler)
+	if len(scopes) > 0 {
+		toolScopes[tool.Name] = scopes
+	}
+}
+
+// parseScopeMap parses the "key=scope scope;key=scope" format shared by
+// AUTH_TOKENS and TOOL_SCOPES: entries are separated by ';', a key from
+// its scopes by the first '=', and scopes by whitespace (the OAuth "scope"
+// parameter syntax). A key with no '=' or an empty scope list maps to no
+// scopes. An empty key is rejected rather than silently ignored, since it
+// almost always means a stray separator in a templated manifest.
+func parseScopeMap(s string) (map[string][]string, error) {
+	m := map[string][]string{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, scopes, _ := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("empty key in entry %q", entry)
+		}
+		m[key] = strings.Fields(scopes)
+	}
+	return m, nil
+}
+
+// verifyBearerToken is the auth.TokenVerifier for AUTH_TOKENS. Tokens are
+// opaque, never expire, and carry exactly the scopes configured for them;
+// the token itself doubles as the UserID so the streamable-http handler
+// binds each session to the token that created it.
+func verifyBearerToken(_ context.Context, token string, _ *http.Request) (*auth.TokenInfo, error) {
+	scopes, ok := authTokens[token]
+	if !ok {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.TokenInfo{Scopes: scopes, UserID: token}, nil
+}
+
+// bearerWrapper enforces AUTH_TOKENS using the SDK's bearer-token
+// middleware, then checks per-tool scopes. It is a passthrough when no
+// tokens are configured, like authWrapper is when AUTH_HEADER is unset.
+func bearerWrapper(next http.Handler) http.Handler {
+	if len(authTokens) == 0 {
+		return next
+	}
+	return auth.RequireBearerToken(verifyBearerToken, &auth.RequireBearerTokenOptions{
+		ResourceMetadataURL:    resourceMetadataURL,
+		AllowMissingExpiration: true,
+	})(scopeWrapper(next))
+}
+
+// scopeWrapper rejects a tools/call whose bearer token lacks any of the
+// scopes the tool requires (see toolScopes) with the 403 insufficient_scope
+// challenge from the MCP authorization spec, naming the missing scopes so
+// a client or gateway can step up and retry. It must sit behind
+// auth.RequireBearerToken, which puts the verified token in the context.
+//
+// Every other request, including bodies that aren't a JSON-RPC message,
+// passes through untouched for the SDK to handle.
+func scopeWrapper(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || len(toolScopes) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		tokenInfo := auth.TokenInfoFromContext(r.Context())
+		var granted []string
+		if tokenInfo != nil {
+			granted = tokenInfo.Scopes
+		}
+		if missing := missingScopes(calledTools(body), granted); len(missing) > 0 {
+			challenge := fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, strings.Join(missing, " "))
+			if resourceMetadataURL != "" {
+				challenge += fmt.Sprintf(", resource_metadata=%q", resourceMetadataURL)
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			http.Error(w, "insufficient_scope", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// calledTools returns the names of the tools invoked by the tools/call
+// messages in body, which may be a single JSON-RPC message or a batch.
+func calledTools(body []byte) []string {
+	type message struct {
+		Method string `json:"method"`
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+
+	var msgs []message
+	if err := json.Unmarshal(body, &msgs); err != nil {
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			return nil
+		}
+		msgs = []message{msg}
+	}
+
+	var names []string
+	for _, msg := range msgs {
+		if msg.Method == methodToolsCall {
+			names = append(names, msg.Params.Name)
+		}
+	}
+	return names
+}
+
+// missingScopes returns the scopes required by tools that are not in
+// granted, in declaration order and without duplicates.
+func missingScopes(tools, granted []string) []string {
+	var missing []string
+	for _, tool := range tools {
+		for _, scope := range toolScopes[tool] {
+			if !slices.Contains(granted, scope) && !slices.Contains(missing, scope) {
+				missing = append(missing, scope)
+			}
+		}
+	}
+	return missing
+}
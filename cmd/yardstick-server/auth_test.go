@@ -0,0 +1,130 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopeMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string][]string
+		wantErr  bool
+	}{
+		{name: "empty", input: "", expected: map[string][]string{}},
+		{name: "single entry", input: "tok=a b", expected: map[string][]string{"tok": {"a", "b"}}},
+		{
+			name:     "multiple entries with whitespace",
+			input:    " tok1 = tools:echo ; tok2=admin ;",
+			expected: map[string][]string{"tok1": {"tools:echo"}, "tok2": {"admin"}},
+		},
+		{name: "no scopes", input: "tok", expected: map[string][]string{"tok": {}}},
+		{name: "empty key rejected", input: "=a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseScopeMap(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestCalledTools(t *testing.T) {
+	assert.Equal(t, []string{"echo"},
+		calledTools([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`)))
+	assert.Equal(t, []string{"echo", "other"},
+		calledTools([]byte(`[{"method":"tools/call","params":{"name":"echo"}},`+
+			`{"method":"tools/list"},{"method":"tools/call","params":{"name":"other"}}]`)))
+	assert.Empty(t, calledTools([]byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)))
+	assert.Empty(t, calledTools([]byte(`not json`)))
+}
+
+// withAuthTokens installs the given bearer tokens and tool scopes for the
+// duration of the test.
+func withAuthTokens(t *testing.T, tokens, scopes map[string][]string) {
+	t.Helper()
+	origTokens, origScopes := authTokens, toolScopes
+	t.Cleanup(func() { authTokens, toolScopes = origTokens, origScopes })
+	authTokens, toolScopes = tokens, scopes
+}
+
+func TestBearerWrapper(t *testing.T) {
+	withAuthTokens(t,
+		map[string][]string{"reader": {"tools:read"}, "writer": {"tools:read", "tools:echo"}},
+		map[string][]string{"echo": {"tools:echo"}})
+
+	var gotBody string
+	handler := bearerWrapper(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	callEcho := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"input":"a"}}}`
+	listTools := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+	tests := []struct {
+		name          string
+		token         string
+		body          string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "missing token", body: listTools, wantStatus: http.StatusUnauthorized},
+		{name: "unknown token", token: "nope", body: listTools, wantStatus: http.StatusUnauthorized},
+		{name: "unscoped method allowed", token: "reader", body: listTools, wantStatus: http.StatusOK},
+		{
+			name:          "insufficient scope",
+			token:         "reader",
+			body:          callEcho,
+			wantStatus:    http.StatusForbidden,
+			wantChallenge: `Bearer error="insufficient_scope", scope="tools:echo"`,
+		},
+		{name: "sufficient scope", token: "writer", body: callEcho, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBody = ""
+			req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantChallenge, rec.Header().Get("WWW-Authenticate"))
+			if tt.wantStatus == http.StatusOK {
+				// The body must reach the handler intact after being inspected.
+				assert.Equal(t, tt.body, gotBody)
+			}
+		})
+	}
+}
+
+func TestBearerWrapper_DisabledWithoutTokens(t *testing.T) {
+	withAuthTokens(t, map[string][]string{}, map[string][]string{"echo": {"tools:echo"}})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"method":"tools/call","params":{"name":"echo"}}`))
+	rec := httptest.NewRecorder()
+
+	bearerWrapper(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	notificationCancelled = "notifications/cancelled"
+	waitForCancelTool     = "wait_for_cancel"
+	cancellationLogTool   = "cancellation_log"
+
+	defaultCancelWait = 60000
+	maxCancelWait     = 600000
+	// maxCancellationLog bounds how many calls the log remembers; older
+	// entries are dropped first.
+	maxCancellationLog = 100
+
+	// cancelledGrace is how long a request that ended cancelled stays in
+	// flight for matching, and how long an unmatched notification waits
+	// for a call to be cancelled. The SDK cancels the request from its own
+	// goroutine, so the request and the notification that cancelled it can
+	// reach the tracker in either order, but not far apart.
+	cancelledGrace = 100 * time.Millisecond
+
+	outcomeRunning   = "running"
+	outcomeCancelled = "cancelled"
+	outcomeTimedOut  = "timed_out"
+)
+
+// WaitForCancelRequest represents the request for the wait_for_cancel tool
+type WaitForCancelRequest struct {
+	TimeoutMS int `json:"timeout_ms,omitempty"`
+}
+
+// WaitForCancelResponse represents the response from the wait_for_cancel
+// tool, which is only sent if the call was not cancelled.
+type WaitForCancelResponse struct {
+	Call   int    `json:"call"`
+	RanMS  int64  `json:"ran_ms"`
+	Result string `json:"result"`
+}
+
+// CancellationEntry records one wait_for_cancel call.
+type CancellationEntry struct {
+	// Call numbers wait_for_cancel calls from 1 in the order they started.
+	Call      int       `json:"call"`
+	SessionID string    `json:"session_id,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// Outcome is running, cancelled (the call's context was cancelled) or
+	// timed_out.
+	Outcome string `json:"outcome"`
+	// RanMS is how long the call ran before its context was cancelled or it
+	// timed out.
+	RanMS int64 `json:"ran_ms,omitempty"`
+	// Notified reports whether a notifications/cancelled arrived for the
+	// call. A cancelled call that was not notified was cancelled some other
+	// way, for instance by its session or connection closing.
+	Notified bool `json:"notified"`
+	// RequestID and Reason are those of the notifications/cancelled.
+	RequestID any    `json:"request_id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+
+	session mcp.Session
+}
+
+// CancellationLogRequest represents the request for the cancellation_log
+// tool
+type CancellationLogRequest struct {
+	Clear bool `json:"clear,omitempty"`
+}
+
+// CancellationLogResponse represents the response from the
+// cancellation_log tool
+type CancellationLogResponse struct {
+	Entries []CancellationEntry `json:"entries"`
+}
+
+// pendingCancel is a notifications/cancelled that has not yet been matched
+// to a call.
+type pendingCancel struct {
+	session   mcp.Session
+	requestID any
+	reason    string
+	at        time.Time
+}
+
+// cancellationTracker pairs wait_for_cancel calls with the
+// notifications/cancelled that cancel them. The SDK doesn't tell
+// middleware or tool handlers a request's ID, so the two are matched per
+// session instead: a notification goes to the session's oldest unnotified
+// call, and one that arrives with no such call waits, for up to
+// cancelledGrace, for the next call in its session to be cancelled. A
+// notification that arrives while the
+// session has any other request in flight could be for that request, so
+// it isn't matched at all.
+type cancellationTracker struct {
+	mu      sync.Mutex
+	calls   int
+	entries []*CancellationEntry
+	pending []pendingCancel
+	// others counts each session's requests in flight, other than calls
+	// to wait_for_cancel and cancellation_log. Sessions with none are left
+	// out.
+	others map[mcp.Session]int
+}
+
+func (t *cancellationTracker) start(session mcp.Session) *CancellationEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.calls++
+	entry := &CancellationEntry{
+		Call:      t.calls,
+		StartedAt: time.Now(),
+		Outcome:   outcomeRunning,
+		session:   session,
+	}
+	if session != nil {
+		entry.SessionID = session.ID()
+	}
+	t.entries = append(t.entries, entry)
+	if len(t.entries) > maxCancellationLog {
+		t.entries = slices.Delete(t.entries, 0, len(t.entries)-maxCancellationLog)
+	}
+	return entry
+}
+
+func (t *cancellationTracker) finish(entry *CancellationEntry, outcome string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry.Outcome = outcome
+	entry.RanMS = time.Since(entry.StartedAt).Milliseconds()
+	if outcome != outcomeCancelled || entry.Notified {
+		return
+	}
+	t.dropStalePending()
+	for i, p := range t.pending {
+		if p.session == entry.session {
+			entry.Notified, entry.RequestID, entry.Reason = true, p.requestID, p.reason
+			t.pending = slices.Delete(t.pending, i, i+1)
+			return
+		}
+	}
+}
+
+func (t *cancellationTracker) notified(session mcp.Session, params *mcp.CancelledParams) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.others[session] > 0 {
+		return
+	}
+	for _, entry := range t.entries {
+		if entry.session == session && !entry.Notified &&
+			(entry.Outcome == outcomeRunning || entry.Outcome == outcomeCancelled) {
+			entry.Notified, entry.RequestID, entry.Reason = true, params.RequestID, params.Reason
+			return
+		}
+	}
+	t.dropStalePending()
+	t.pending = append(t.pending, pendingCancel{
+		session: session, requestID: params.RequestID, reason: params.Reason, at: time.Now(),
+	})
+	if len(t.pending) > maxCancellationLog {
+		t.pending = slices.Delete(t.pending, 0, len(t.pending)-maxCancellationLog)
+	}
+}
+
+// dropStalePending drops notifications that have waited longer than
+// cancelledGrace, which were for requests that had already ended. t.mu
+// must be held.
+func (t *cancellationTracker) dropStalePending() {
+	t.pending = slices.DeleteFunc(t.pending, func(p pendingCancel) bool {
+		return time.Since(p.at) > cancelledGrace
+	})
+}
+
+func (t *cancellationTracker) snapshot(clear bool) []CancellationEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entries := make([]CancellationEntry, len(t.entries))
+	for i, entry := range t.entries {
+		entries[i] = *entry
+	}
+	if clear {
+		t.entries, t.pending = nil, nil
+	}
+	return entries
+}
+
+// begin and end count a request other than a call to wait_for_cancel or
+// cancellation_log in and out of flight.
+func (t *cancellationTracker) begin(session mcp.Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.others == nil {
+		t.others = map[mcp.Session]int{}
+	}
+	t.others[session]++
+}
+
+func (t *cancellationTracker) end(session mcp.Session) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.others[session]--; t.others[session] <= 0 {
+		delete(t.others, session)
+	}
+}
+
+// middleware counts the other requests in flight that a
+// notifications/cancelled could be for, and passes each
+// notifications/cancelled to notified.
+func (t *cancellationTracker) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		switch {
+		case method == notificationCancelled:
+			if params, ok := req.GetParams().(*mcp.CancelledParams); ok && params != nil {
+				t.notified(req.GetSession(), params)
+			}
+		case strings.HasPrefix(method, "notifications/") || isTrackerCall(method, req):
+		default:
+			session := req.GetSession()
+			t.begin(session)
+			defer func() {
+				if ctx.Err() != nil {
+					time.AfterFunc(cancelledGrace, func() { t.end(session) })
+					return
+				}
+				t.end(session)
+			}()
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// isTrackerCall reports whether req calls wait_for_cancel or
+// cancellation_log, which a test polls while its calls are cancelled.
+func isTrackerCall(method string, req mcp.Request) bool {
+	if method != methodToolsCall {
+		return false
+	}
+	params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+	return ok && params != nil && (params.Name == waitForCancelTool || params.Name == cancellationLogTool)
+}
+
+func (t *cancellationTracker) waitHandler(
+	ctx context.Context, req *mcp.CallToolRequest, params WaitForCancelRequest,
+) (*mcp.CallToolResult, WaitForCancelResponse, error) {
+	timeout := params.TimeoutMS
+	if timeout == 0 {
+		timeout = defaultCancelWait
+	}
+	if timeout < 1 || timeout > maxCancelWait {
+		return nil, WaitForCancelResponse{}, fmt.Errorf("timeout_ms must be between 1 and %d (got %d)",
+			maxCancelWait, timeout)
+	}
+
+	var session mcp.Session
+	if req.Session != nil {
+		session = req.Session
+	}
+	entry := t.start(session)
+	timer := time.NewTimer(time.Duration(timeout) * time.Millisecond)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		t.finish(entry, outcomeCancelled)
+		return nil, WaitForCancelResponse{}, ctx.Err()
+	case <-timer.C:
+		t.finish(entry, outcomeTimedOut)
+		return nil, WaitForCancelResponse{
+			Call:   entry.Call,
+			RanMS:  entry.RanMS,
+			Result: fmt.Sprintf("not cancelled within %d ms", timeout),
+		}, nil
+	}
+}
+
+func (t *cancellationTracker) logHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params CancellationLogRequest,
+) (*mcp.CallToolResult, CancellationLogResponse, error) {
+	return nil, CancellationLogResponse{Entries: t.snapshot(params.Clear)}, nil
+}
+
+// addCancellationTools registers wait_for_cancel and cancellation_log,
+// and the middleware that lets the log tell notified cancellations apart.
+func addCancellationTools(server *mcp.Server) {
+	tracker := &cancellationTracker{}
+	server.AddReceivingMiddleware(tracker.middleware)
+
+	addTool(server, &mcp.Tool{
+		Name: waitForCancelTool,
+		Description: "Block until the call is cancelled or timeout_ms passes, recording in the cancellation log " +
+			"whether a notifications/cancelled arrived and how long the call ran.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"timeout_ms": boundedProp("integer", "Milliseconds to wait before giving up", 1, maxCancelWait,
+				defaultCancelWait),
+		}),
+	}, tracker.waitHandler)
+
+	addTool(server, &mcp.Tool{
+		Name: cancellationLogTool,
+		Description: fmt.Sprintf("List the last %d wait_for_cancel calls: how each ended, how long it ran, "+
+			"and whether a notifications/cancelled arrived for it.", maxCancellationLog),
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"clear": {Type: "boolean", Default: rawDefault(false), Description: "Empty the log after listing it"},
+		}),
+	}, tracker.logHandler)
+}
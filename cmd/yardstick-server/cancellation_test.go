@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+// TestCancellationTracker_Matching covers both orders a call's
+// cancellation and its notification can be seen in.
+func TestCancellationTracker_Matching(t *testing.T) {
+	tracker := &cancellationTracker{}
+	a, b := &mcp.ServerSession{}, &mcp.ServerSession{}
+
+	// Notification first, while the call still runs.
+	first := tracker.start(a)
+	tracker.notified(a, &mcp.CancelledParams{RequestID: 1, Reason: "user"})
+	tracker.finish(first, outcomeCancelled)
+
+	// Cancellation first; the notification for another session must not
+	// be credited to it.
+	second := tracker.start(a)
+	tracker.finish(second, outcomeCancelled)
+	tracker.notified(b, &mcp.CancelledParams{RequestID: 7})
+	tracker.notified(a, &mcp.CancelledParams{RequestID: 2})
+
+	// A notification that arrives before its call is cancelled, with no
+	// call running, is matched when the call is.
+	third := tracker.start(b)
+	tracker.finish(third, outcomeCancelled)
+
+	// Cancelled without notification, and timed out.
+	fourth := tracker.start(a)
+	tracker.finish(fourth, outcomeCancelled)
+	fifth := tracker.start(a)
+	tracker.finish(fifth, outcomeTimedOut)
+
+	entries := tracker.snapshot(true)
+	require.Len(t, entries, 5)
+	assert.True(t, entries[0].Notified)
+	assert.Equal(t, 1, entries[0].RequestID)
+	assert.Equal(t, "user", entries[0].Reason)
+	assert.True(t, entries[1].Notified)
+	assert.Equal(t, 2, entries[1].RequestID)
+	assert.True(t, entries[2].Notified)
+	assert.Equal(t, 7, entries[2].RequestID)
+	assert.False(t, entries[3].Notified)
+	assert.Equal(t, outcomeCancelled, entries[3].Outcome)
+	assert.False(t, entries[4].Notified)
+	assert.Equal(t, outcomeTimedOut, entries[4].Outcome)
+
+	assert.Empty(t, tracker.snapshot(false))
+}
+
+// TestCancellationTracker_OtherRequests checks a notification isn't
+// matched while the session has another request in flight it could be for.
+func TestCancellationTracker_OtherRequests(t *testing.T) {
+	tracker := &cancellationTracker{}
+	a, b := &mcp.ServerSession{}, &mcp.ServerSession{}
+
+	entry := tracker.start(a)
+	tracker.begin(a)
+	tracker.notified(a, &mcp.CancelledParams{RequestID: 1})
+	tracker.end(a)
+	assert.Empty(t, tracker.others)
+	tracker.begin(b)
+	tracker.notified(a, &mcp.CancelledParams{RequestID: 2})
+	tracker.finish(entry, outcomeCancelled)
+
+	entries := tracker.snapshot(false)
+	require.Len(t, entries, 1)
+	assert.True(t, entries[0].Notified)
+	assert.Equal(t, 2, entries[0].RequestID)
+}
+
+// TestCancellationTracker_StalePending checks a notification that matched
+// nothing isn't credited to a call cancelled long after it arrived.
+func TestCancellationTracker_StalePending(t *testing.T) {
+	tracker := &cancellationTracker{}
+	a := &mcp.ServerSession{}
+
+	tracker.notified(a, &mcp.CancelledParams{RequestID: 1})
+	time.Sleep(2 * cancelledGrace)
+	entry := tracker.start(a)
+	tracker.finish(entry, outcomeCancelled)
+	assert.False(t, entry.Notified)
+	assert.Empty(t, tracker.pending)
+}
+
+// TestWaitForCancel_OtherRequestCancelled cancels a progress call while a
+// wait_for_cancel call runs in the same session, and checks the progress
+// call's notification isn't credited to wait_for_cancel.
+func TestWaitForCancel_OtherRequestCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectInMemory(ctx, t, nil, func(server *mcp.Server) {
+		addCancellationTools(server)
+		addProgressTool(server)
+	}, nil)
+
+	lastEntry := func() map[string]any {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "cancellation_log", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		entries := result.StructuredContent.(map[string]any)["entries"].([]any)
+		if len(entries) == 0 {
+			return nil
+		}
+		return entries[len(entries)-1].(map[string]any)
+	}
+
+	waitCtx, cancelWait := context.WithCancel(ctx)
+	waitDone := make(chan error, 1)
+	go func() {
+		_, err := session.CallTool(waitCtx, &mcp.CallToolParams{Name: "wait_for_cancel", Arguments: map[string]any{}})
+		waitDone <- err
+	}()
+	require.Eventually(t, func() bool { return lastEntry() != nil }, time.Second, 10*time.Millisecond)
+
+	progressCtx, cancelProgress := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelProgress()
+	_, err := session.CallTool(progressCtx, &mcp.CallToolParams{
+		Name: "progress", Arguments: map[string]any{"steps": 10, "interval_ms": 1000},
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	time.Sleep(2 * cancelledGrace)
+	entry := lastEntry()
+	assert.Equal(t, outcomeRunning, entry["outcome"])
+	assert.Equal(t, false, entry["notified"])
+
+	cancelWait()
+	require.ErrorIs(t, <-waitDone, context.Canceled)
+	require.Eventually(t, func() bool {
+		entry = lastEntry()
+		return entry["outcome"] == outcomeCancelled && entry["notified"] == true
+	}, time.Second, 10*time.Millisecond, "last entry: %v", entry)
+}
+
+func TestCancellationTracker_Bounded(t *testing.T) {
+	tracker := &cancellationTracker{}
+	for range maxCancellationLog + 5 {
+		tracker.finish(tracker.start(nil), outcomeTimedOut)
+	}
+	entries := tracker.snapshot(false)
+	require.Len(t, entries, maxCancellationLog)
+	assert.Equal(t, 6, entries[0].Call)
+}
+
+func TestWaitForCancel_TimesOut(t *testing.T) {
+	tracker := &cancellationTracker{}
+	_, got, err := tracker.waitHandler(context.Background(), &mcp.CallToolRequest{}, WaitForCancelRequest{TimeoutMS: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Call)
+	assert.GreaterOrEqual(t, got.RanMS, int64(10))
+
+	_, _, err = tracker.waitHandler(context.Background(), &mcp.CallToolRequest{},
+		WaitForCancelRequest{TimeoutMS: maxCancelWait + 1})
+	assert.ErrorContains(t, err, "timeout_ms")
+}
+
+// TestWaitForCancel_Transports cancels a call from the client over each
+// HTTP-based transport and checks the server saw the notification.
+func TestWaitForCancel_Transports(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addCancellationTools(server)
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	transports := map[string]func() mcp.Transport{
+		transportSSE: func() mcp.Transport { return &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath} },
+		transportStreamableHTTP: func() mcp.Transport {
+			return &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath}
+		},
+		transportWebSocket: func() mcp.Transport { return &wsconn.ClientTransport{URL: httpServer.URL + wsPath} },
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport(), nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			callCtx, cancelCall := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancelCall()
+			_, err = session.CallTool(callCtx, &mcp.CallToolParams{Name: "wait_for_cancel", Arguments: map[string]any{}})
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			var entry map[string]any
+			require.Eventually(t, func() bool {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{
+					Name: "cancellation_log", Arguments: map[string]any{"clear": false},
+				})
+				if err != nil || result.IsError {
+					return false
+				}
+				entries := result.StructuredContent.(map[string]any)["entries"].([]any)
+				if len(entries) == 0 {
+					return false
+				}
+				entry = entries[len(entries)-1].(map[string]any)
+				return entry["outcome"] == outcomeCancelled && entry["notified"] == true
+			}, 2*time.Second, 20*time.Millisecond, "last entry: %v", entry)
+			assert.GreaterOrEqual(t, entry["ran_ms"], 50.0)
+			assert.Equal(t, context.DeadlineExceeded.Error(), entry["reason"])
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	catalogTool     = "tool"
+	catalogResource = "resource"
+	catalogPrompt   = "prompt"
+
+	catalogAdd    = "add"
+	catalogRemove = "remove"
+	catalogRename = "rename"
+
+	// dynamicPrefix starts the name of every item change_catalog adds, so
+	// they can't collide with, or be used to remove, the fixed ones.
+	dynamicPrefix = "dynamic_"
+	// dynamicResourceBase is the URI of a dynamic resource, less its name.
+	dynamicResourceBase = "yardstick://dynamic/"
+
+	// catalogScope is the scope a bearer token needs to call
+	// change_catalog, which changes the server for every session.
+	catalogScope = "catalog:write"
+)
+
+// catalogNamePattern is what change_catalog accepts as a name; the
+// dynamic_ prefix is added to it.
+var catalogNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ChangeCatalogRequest represents the request for the change_catalog tool
+type ChangeCatalogRequest struct {
+	Kind    string `json:"kind"`
+	Action  string `json:"action"`
+	Name    string `json:"name"`
+	NewName string `json:"new_name,omitempty"`
+}
+
+// ChangeCatalogResponse represents the response from the change_catalog
+// tool
+type ChangeCatalogResponse struct {
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	// Generation counts the changes made since the server started, across
+	// all kinds, so a client can tell which change a catalog reflects.
+	Generation   int    `json:"generation"`
+	Notification string `json:"notification"`
+	// Items lists the dynamic items of this kind after the change, by name
+	// (or URI, for resources), in order.
+	Items []string `json:"items"`
+}
+
+// catalog tracks the tools, resources and prompts change_catalog added to
+// a running server. Each change goes through the SDK's own add and remove
+// methods, which send the list_changed notification; changes made within
+// a few milliseconds of each other are sent as one.
+type catalog struct {
+	server *mcp.Server
+
+	mu         sync.Mutex
+	generation int
+	items      map[string]map[string]bool
+}
+
+func newCatalog(server *mcp.Server) *catalog {
+	return &catalog{server: server, items: map[string]map[string]bool{
+		catalogTool: {}, catalogResource: {}, catalogPrompt: {},
+	}}
+}
+
+// listChangedNotification returns the notification a change to kind sends.
+func listChangedNotification(kind string) string {
+	return "notifications/" + kind + "s/list_changed"
+}
+
+// change applies one change_catalog request. A rename removes the old item
+// and adds the new one, which the SDK batches into one notification.
+func (c *catalog) change(req ChangeCatalogRequest) (ChangeCatalogResponse, error) {
+	items, ok := c.items[req.Kind]
+	if !ok {
+		return ChangeCatalogResponse{}, fmt.Errorf("unknown kind %q: valid values are %s, %s, %s",
+			req.Kind, catalogTool, catalogResource, catalogPrompt)
+	}
+	if !catalogNamePattern.MatchString(req.Name) {
+		return ChangeCatalogResponse{}, fmt.Errorf("name %q must match %s", req.Name, catalogNamePattern)
+	}
+	name := dynamicPrefix + req.Name
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch req.Action {
+	case catalogAdd:
+		if items[name] {
+			return ChangeCatalogResponse{}, fmt.Errorf("%s %s already exists", req.Kind, name)
+		}
+		c.add(req.Kind, name)
+	case catalogRemove:
+		if !items[name] {
+			return ChangeCatalogResponse{}, fmt.Errorf("no dynamic %s %s", req.Kind, name)
+		}
+		c.remove(req.Kind, name)
+	case catalogRename:
+		if !catalogNamePattern.MatchString(req.NewName) {
+			return ChangeCatalogResponse{}, fmt.Errorf("new_name %q must match %s", req.NewName, catalogNamePattern)
+		}
+		newName := dynamicPrefix + req.NewName
+		switch {
+		case !items[name]:
+			return ChangeCatalogResponse{}, fmt.Errorf("no dynamic %s %s", req.Kind, name)
+		case items[newName]:
+			return ChangeCatalogResponse{}, fmt.Errorf("%s %s already exists", req.Kind, newName)
+		}
+		c.remove(req.Kind, name)
+		c.add(req.Kind, newName)
+	default:
+		return ChangeCatalogResponse{}, fmt.Errorf("unknown action %q: valid values are %s, %s, %s",
+			req.Action, catalogAdd, catalogRemove, catalogRename)
+	}
+	c.generation++
+
+	response := ChangeCatalogResponse{
+		Kind:         req.Kind,
+		Action:       req.Action,
+		Generation:   c.generation,
+		Notification: listChangedNotification(req.Kind),
+		Items:        []string{},
+	}
+	for _, name := range slices.Sorted(maps.Keys(items)) {
+		if req.Kind == catalogResource {
+			name = dynamicResourceBase + name
+		}
+		response.Items = append(response.Items, name)
+	}
+	return response, nil
+}
+
+// add registers a dynamic item. Like the synthetic items, each one says
+// what it is when used, so a gateway serving a stale catalog is caught
+// calling something that has gone or been renamed.
+func (c *catalog) add(kind, name string) {
+	c.items[kind][name] = true
+	text := fmt.Sprintf("dynamic %s %s", kind, name)
+	switch kind {
+	case catalogTool:
+		addTool(c.server, &mcp.Tool{
+			Name:        name,
+			Description: "A tool added at runtime by change_catalog",
+			InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{}),
+		}, func(_ context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		})
+	case catalogResource:
+		c.server.AddResource(&mcp.Resource{
+			URI:         dynamicResourceBase + name,
+			Name:        name,
+			MIMEType:    mimeText,
+			Description: "A resource added at runtime by change_catalog",
+			Size:        int64(len(text)),
+		}, resourceHandler(textContents(text)))
+	case catalogPrompt:
+		c.server.AddPrompt(&mcp.Prompt{
+			Name:        name,
+			Description: "A prompt added at runtime by change_catalog",
+		}, func(_ context.Context, _ *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: text}},
+			}}, nil
+		})
+	}
+}
+
+func (c *catalog) remove(kind, name string) {
+	delete(c.items[kind], name)
+	switch kind {
+	case catalogTool:
+		c.server.RemoveTools(name)
+	case catalogResource:
+		c.server.RemoveResources(dynamicResourceBase + name)
+	case catalogPrompt:
+		c.server.RemovePrompts(name)
+	}
+}
+
+func (c *catalog) changeHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params ChangeCatalogRequest,
+) (*mcp.CallToolResult, ChangeCatalogResponse, error) {
+	response, err := c.change(params)
+	return nil, response, err
+}
+
+// addCatalogTool registers change_catalog, which changes server's own
+// tools, resources and prompts.
+func addCatalogTool(server *mcp.Server) {
+	c := newCatalog(server)
+	addTool(server, &mcp.Tool{
+		Name: "change_catalog",
+		Description: "Add, remove or rename a tool, resource or prompt on this server, which then sends " +
+			"notifications/tools/list_changed (or resources, or prompts) to every session. " +
+			"Only items this tool added can be removed or renamed; their names all start with " + dynamicPrefix +
+			", and dynamic resources live under " + dynamicResourceBase + ".",
+		InputSchema: objectSchema([]string{"kind", "action", "name"}, map[string]*jsonschema.Schema{
+			"kind": enumProp("What to change", "", catalogTool, catalogResource, catalogPrompt),
+			"action": enumProp("add creates the item; remove deletes it; rename replaces it with new_name", "",
+				catalogAdd, catalogRemove, catalogRename),
+			"name": {
+				Type:        "string",
+				Pattern:     catalogNamePattern.String(),
+				Description: "Name of the item, without the " + dynamicPrefix + " prefix",
+			},
+			"new_name": {
+				Type:        "string",
+				Pattern:     catalogNamePattern.String(),
+				Description: "New name of the item, without the " + dynamicPrefix + " prefix (for rename)",
+			},
+		}),
+	}, c.changeHandler, catalogScope)
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+// TestChangeCatalog_Scope checks that change_catalog declares its scope,
+// so a token without it gets the step-up challenge with no TOOL_SCOPES.
+func TestChangeCatalog_Scope(t *testing.T) {
+	withAuthTokens(t, map[string][]string{"reader": {"tools:read"}}, map[string][]string{})
+	addCatalogTool(mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil))
+	assert.Equal(t, []string{catalogScope}, toolScopes["change_catalog"])
+
+	handler := bearerWrapper(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"change_catalog"}}`))
+	req.Header.Set("Authorization", "Bearer reader")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, `Bearer error="insufficient_scope", scope="catalog:write"`, rec.Header().Get("WWW-Authenticate"))
+}
+
+func TestCatalog_Change(t *testing.T) {
+	c := newCatalog(mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil))
+
+	tests := []struct {
+		req     ChangeCatalogRequest
+		items   []string
+		wantErr string
+	}{
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogAdd, Name: "b"}, items: []string{"dynamic_b"}},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogAdd, Name: "a"}, items: []string{"dynamic_a", "dynamic_b"}},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogAdd, Name: "a"}, wantErr: "tool dynamic_a already exists"},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogRename, Name: "b", NewName: "c"},
+			items: []string{"dynamic_a", "dynamic_c"}},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogRename, Name: "a", NewName: "c"},
+			wantErr: "tool dynamic_c already exists"},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogRename, Name: "a"}, wantErr: `new_name "" must match`},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogRemove, Name: "b"}, wantErr: "no dynamic tool dynamic_b"},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogRemove, Name: "a"}, items: []string{"dynamic_c"}},
+		{req: ChangeCatalogRequest{Kind: catalogResource, Action: catalogAdd, Name: "r"},
+			items: []string{"yardstick://dynamic/dynamic_r"}},
+		{req: ChangeCatalogRequest{Kind: catalogPrompt, Action: catalogAdd, Name: "c"}, items: []string{"dynamic_c"}},
+		{req: ChangeCatalogRequest{Kind: catalogPrompt, Action: catalogRemove, Name: "c"}, items: []string{}},
+		{req: ChangeCatalogRequest{Kind: "widget", Action: catalogAdd, Name: "a"}, wantErr: `unknown kind "widget"`},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: "copy", Name: "a"}, wantErr: `unknown action "copy"`},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogAdd, Name: "has space"}, wantErr: "must match"},
+		{req: ChangeCatalogRequest{Kind: catalogTool, Action: catalogAdd}, wantErr: `name "" must match`},
+	}
+	generation := 0
+	for _, tt := range tests {
+		got, err := c.change(tt.req)
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, "%+v", tt.req)
+			continue
+		}
+		require.NoError(t, err, "%+v", tt.req)
+		generation++
+		assert.Equal(t, ChangeCatalogResponse{
+			Kind:         tt.req.Kind,
+			Action:       tt.req.Action,
+			Generation:   generation,
+			Notification: "notifications/" + tt.req.Kind + "s/list_changed",
+			Items:        tt.items,
+		}, got, "%+v", tt.req)
+	}
+}
+
+// TestChangeCatalog_Legacy changes each kind of item over a 2025-11-25
+// session, which gets list_changed notifications without asking, and
+// checks the lists and the items themselves follow.
+func TestChangeCatalog_Legacy(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addCatalogTool(server)
+	c := newLegacyConn(ctx, t, server)
+
+	change := func(t *testing.T, kind, action, name, newName string) {
+		t.Helper()
+		args := map[string]any{"kind": kind, "action": action, "name": name}
+		if newName != "" {
+			args["new_name"] = newName
+		}
+		resp, _ := c.call(ctx, "tools/call", map[string]any{"name": "change_catalog", "arguments": args})
+		require.NoError(t, resp.Error)
+		var result mcp.CallToolResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		require.False(t, result.IsError, "%s %s %s: %v", action, kind, name, result.Content)
+		// The SDK sends the notification shortly after the change.
+		msg, err := c.conn.Read(ctx)
+		require.NoError(t, err)
+		require.IsType(t, &jsonrpc.Request{}, msg)
+		assert.Equal(t, listChangedNotification(kind), msg.(*jsonrpc.Request).Method)
+	}
+
+	tests := []struct {
+		kind string
+		list string
+		use  func(name string) (string, map[string]any)
+	}{
+		{catalogTool, "tools/list", func(name string) (string, map[string]any) {
+			return "tools/call", map[string]any{"name": name, "arguments": map[string]any{}}
+		}},
+		{catalogResource, "resources/list", func(name string) (string, map[string]any) {
+			return "resources/read", map[string]any{"uri": dynamicResourceBase + name}
+		}},
+		{catalogPrompt, "prompts/list", func(name string) (string, map[string]any) {
+			return "prompts/get", map[string]any{"name": name}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			change(t, tt.kind, catalogAdd, "first", "")
+			resp, _ := c.call(ctx, tt.list, map[string]any{})
+			require.NoError(t, resp.Error)
+			assert.Contains(t, string(resp.Result), "dynamic_first")
+			method, params := tt.use("dynamic_first")
+			resp, _ = c.call(ctx, method, params)
+			require.NoError(t, resp.Error)
+			assert.Contains(t, string(resp.Result), "dynamic "+tt.kind+" dynamic_first")
+
+			change(t, tt.kind, catalogRename, "first", "second")
+			resp, _ = c.call(ctx, tt.list, map[string]any{})
+			require.NoError(t, resp.Error)
+			assert.NotContains(t, string(resp.Result), "dynamic_first")
+			assert.Contains(t, string(resp.Result), "dynamic_second")
+			method, params = tt.use("dynamic_second")
+			resp, _ = c.call(ctx, method, params)
+			require.NoError(t, resp.Error)
+			assert.Contains(t, string(resp.Result), "dynamic "+tt.kind+" dynamic_second")
+
+			change(t, tt.kind, catalogRemove, "second", "")
+			resp, _ = c.call(ctx, tt.list, map[string]any{})
+			require.NoError(t, resp.Error)
+			assert.NotContains(t, string(resp.Result), "dynamic_second")
+		})
+	}
+}
+
+// TestChangeCatalog_Transports listens for tools/list_changed with the
+// SDK's client, which opens a subscriptions/listen stream for it on the
+// 2026-07-28 protocol, over each HTTP-based transport.
+func TestChangeCatalog_Transports(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addCatalogTool(server)
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	transports := map[string]func() mcp.Transport{
+		transportSSE: func() mcp.Transport { return &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath} },
+		transportStreamableHTTP: func() mcp.Transport {
+			return &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath}
+		},
+		transportWebSocket: func() mcp.Transport { return &wsconn.ClientTransport{URL: httpServer.URL + wsPath} },
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			var changes atomic.Int64
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, &mcp.ClientOptions{
+				ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) { changes.Add(1) },
+			})
+			session, err := client.Connect(ctx, transport(), nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			toggle := func(action string) {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "change_catalog", Arguments: map[string]any{
+					"kind": catalogTool, "action": action, "name": name,
+				}})
+				require.NoError(t, err)
+				require.False(t, result.IsError, "%v", result.Content)
+			}
+			// On the 2026-07-28 protocol the listen stream is opened in the
+			// background, so keep changing the list until a notification
+			// gets through.
+			require.Eventually(t, func() bool {
+				toggle(catalogAdd)
+				toggle(catalogRemove)
+				time.Sleep(20 * time.Millisecond)
+				return changes.Load() > 0
+			}, 2*time.Second, 10*time.Millisecond)
+
+			seen := changes.Load()
+			toggle(catalogAdd)
+			require.Eventually(t, func() bool { return changes.Load() > seen }, time.Second, 10*time.Millisecond)
+			tools, err := session.ListTools(ctx, nil)
+			require.NoError(t, err)
+			var names []string
+			for _, tool := range tools.Tools {
+				names = append(names, tool.Name)
+			}
+			assert.Contains(t, names, dynamicPrefix+name)
+			toggle(catalogRemove)
+		})
+	}
+}
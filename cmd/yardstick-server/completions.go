@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	refPrompt   = "ref/prompt"
+	refResource = "ref/resource"
+
+	// maxCompletionValues is the most values a completion returns, the
+	// limit the spec sets. total still counts every match, and hasMore
+	// says some were left out.
+	maxCompletionValues = 100
+	// completionSeeds is how many payload seeds are suggested, enough that
+	// an empty prefix matches more than one response can hold.
+	completionSeeds = 1000
+)
+
+// natoAlphabet is the vocabulary for free-text arguments.
+var natoAlphabet = []string{
+	"alfa", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliett", "kilo", "lima",
+	"mike", "november", "oscar", "papa", "quebec", "romeo", "sierra", "tango", "uniform", "victor", "whiskey",
+	"xray", "yankee", "zulu",
+}
+
+// completionVocabulary returns what each argument of each prompt and
+// variable of each resource template completes from, keyed by reference
+// type, then prompt name or URI template, then argument name. Every list
+// is in the order it is suggested in.
+func completionVocabulary() map[string]map[string]map[string][]string {
+	var uris []string
+	for _, r := range staticResources() {
+		uris = append(uris, r.resource.URI)
+	}
+	var turns []string
+	for i := 1; i <= maxConversationTurns; i++ {
+		turns = append(turns, strconv.Itoa(i))
+	}
+	seeds := make([]string, completionSeeds)
+	for i := range seeds {
+		seeds[i] = strconv.Itoa(i)
+	}
+	var sizes []string
+	for size := 1; size <= maxPayloadBytes; size *= 2 {
+		sizes = append(sizes, strconv.Itoa(size))
+	}
+
+	return map[string]map[string]map[string][]string{
+		refPrompt: {
+			echoHelperPrompt.Name: {"text": natoAlphabet},
+			reviewResourcePrompt.Name: {
+				"uri":   uris,
+				"focus": {"accuracy", "clarity", "encoding", "formatting", "length", "structure"},
+			},
+			conversationPrompt.Name: {
+				"topic": {"caching", "gateways", "latency", "pagination", "rulers", "streaming", "transports"},
+				"turns": turns,
+			},
+		},
+		refResource: {
+			echoResourceTemplate:    {"value": natoAlphabet},
+			payloadResourceTemplate: {"seed": seeds, "size": sizes},
+		},
+	}
+}
+
+// completeValues returns the values in vocabulary that start with prefix,
+// case-sensitively and in order, at most maxCompletionValues of them.
+func completeValues(vocabulary []string, prefix string) mcp.CompletionResultDetails {
+	result := mcp.CompletionResultDetails{Values: []string{}}
+	for _, v := range vocabulary {
+		if !strings.HasPrefix(v, prefix) {
+			continue
+		}
+		result.Total++
+		if len(result.Values) < maxCompletionValues {
+			result.Values = append(result.Values, v)
+		}
+	}
+	result.HasMore = result.Total > len(result.Values)
+	return result
+}
+
+func invalidCompletion(format string, args ...any) error {
+	return &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// completionHandler answers completion/complete for the fixed prompts and
+// the resource templates. A reference to anything else, or to an argument
+// it doesn't have, fails with -32602 rather than completing to nothing, so
+// a gateway that rewrites references is caught.
+func completionHandler(_ context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	ref, arg := req.Params.Ref, req.Params.Argument
+	key := ref.Name
+	if ref.Type == refResource {
+		key = ref.URI
+	}
+	refs, ok := completionVocabulary()[ref.Type]
+	if !ok {
+		return nil, invalidCompletion("unknown reference type %q", ref.Type)
+	}
+	args, ok := refs[key]
+	if !ok {
+		if ref.Type == refResource {
+			return nil, invalidCompletion("no resource template %q", key)
+		}
+		return nil, invalidCompletion("no prompt %q", key)
+	}
+	vocabulary, ok := args[arg.Name]
+	if !ok {
+		return nil, invalidCompletion("%q has no argument %q", key, arg.Name)
+	}
+	return &mcp.CompleteResult{Completion: completeValues(vocabulary, arg.Value)}, nil
+}
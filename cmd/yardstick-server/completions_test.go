@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteValues(t *testing.T) {
+	vocabulary := make([]string, 150)
+	for i := range vocabulary {
+		vocabulary[i] = "v"
+	}
+	got := completeValues(vocabulary, "")
+	assert.Len(t, got.Values, maxCompletionValues)
+	assert.Equal(t, 150, got.Total)
+	assert.True(t, got.HasMore)
+
+	got = completeValues([]string{"apple", "apricot", "banana", "Apple"}, "ap")
+	assert.Equal(t, mcp.CompletionResultDetails{Values: []string{"apple", "apricot"}, Total: 2}, got)
+
+	got = completeValues([]string{"apple"}, "z")
+	assert.Equal(t, mcp.CompletionResultDetails{Values: []string{}}, got)
+}
+
+func TestCompletionHandler(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectInMemory(ctx, t, &mcp.ServerOptions{CompletionHandler: completionHandler}, func(server *mcp.Server) {
+		addPrompts(server)
+		addResources(server)
+	}, nil)
+	assert.NotNil(t, session.InitializeResult().Capabilities.Completions)
+
+	prompt := func(name string) *mcp.CompleteReference { return &mcp.CompleteReference{Type: refPrompt, Name: name} }
+	template := func(uri string) *mcp.CompleteReference { return &mcp.CompleteReference{Type: refResource, URI: uri} }
+	tests := []struct {
+		ref     *mcp.CompleteReference
+		arg     string
+		value   string
+		want    []string
+		total   int
+		hasMore bool
+	}{
+		{ref: prompt("echo_helper"), arg: "text", value: "s", want: []string{"sierra"}, total: 1},
+		{ref: prompt("review_resource"), arg: "uri", value: "yardstick://static/",
+			want: []string{"yardstick://static/hello.txt", "yardstick://static/data.json",
+				"yardstick://static/unicode.md", "yardstick://static/bytes.bin"}, total: 4},
+		{ref: prompt("review_resource"), arg: "focus", value: "", total: 6,
+			want: []string{"accuracy", "clarity", "encoding", "formatting", "length", "structure"}},
+		{ref: prompt("conversation"), arg: "turns", value: "1", want: []string{"1", "10"}, total: 2},
+		{ref: prompt("conversation"), arg: "topic", value: "x", want: []string{}},
+		{ref: template(echoResourceTemplate), arg: "value", value: "ec", want: []string{"echo"}, total: 1},
+		{ref: template(payloadResourceTemplate), arg: "seed", value: "99",
+			want: []string{"99", "990", "991", "992", "993", "994", "995", "996", "997", "998", "999"}, total: 11},
+		{ref: template(payloadResourceTemplate), arg: "size", value: "6",
+			want: []string{"64", "65536", "67108864"}, total: 3},
+	}
+	for _, tt := range tests {
+		result, err := session.Complete(ctx, &mcp.CompleteParams{
+			Ref: tt.ref, Argument: mcp.CompleteParamsArgument{Name: tt.arg, Value: tt.value},
+		})
+		require.NoError(t, err, "%+v %s", tt.ref, tt.arg)
+		assert.Equal(t, mcp.CompletionResultDetails{Values: tt.want, Total: tt.total, HasMore: tt.hasMore},
+			result.Completion, "%+v %s=%q", tt.ref, tt.arg, tt.value)
+	}
+
+	// An empty prefix matches every seed, more than one response holds.
+	result, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref: template(payloadResourceTemplate), Argument: mcp.CompleteParamsArgument{Name: "seed"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Completion.Values, maxCompletionValues)
+	assert.Equal(t, completionSeeds, result.Completion.Total)
+	assert.True(t, result.Completion.HasMore)
+
+	for _, tt := range []struct {
+		ref     *mcp.CompleteReference
+		arg     string
+		wantErr string
+	}{
+		{prompt("missing"), "text", `no prompt "missing"`},
+		{template("yardstick://missing/{x}"), "x", `no resource template "yardstick://missing/{x}"`},
+		{prompt("echo_helper"), "input", `"echo_helper" has no argument "input"`},
+	} {
+		_, err := session.Complete(ctx, &mcp.CompleteParams{Ref: tt.ref, Argument: mcp.CompleteParamsArgument{Name: tt.arg}})
+		var rpcErr *jsonrpc.Error
+		require.True(t, errors.As(err, &rpcErr), "%+v: %v", tt.ref, err)
+		assert.Equal(t, int64(jsonrpc.CodeInvalidParams), rpcErr.Code)
+		assert.Equal(t, tt.wantErr, rpcErr.Message)
+	}
+}
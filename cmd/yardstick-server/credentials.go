@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	credentialTypeHeader = "header"
+	credentialTypeBasic  = "basic"
+	credentialTypeQuery  = "query"
+	credentialTypeBearer = "bearer"
+
+	// credentialNameDefault names the credential built from
+	// AUTH_HEADER/AUTH_VALUE, so whoami can report it like any other.
+	credentialNameDefault = "default"
+)
+
+// credential is one accepted way of authenticating to the HTTP transports
+// (and, presented as a bare value, to stdio; see stdioAuth). Which fields are used depends on Type:
+//   - header: the request carries header Header with exactly Value
+//   - basic:  the request carries HTTP Basic auth for Username/Password
+//   - query:  the request URL carries query parameter Param with exactly Value
+//   - bearer: the request carries "Authorization: Bearer <Value>"
+//
+// Name identifies the credential to tools such as whoami, so a gateway
+// routing several tenants can be checked for forwarding the right one.
+type credential struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Header   string `json:"header,omitempty"`
+	Param    string `json:"param,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Value    string `json:"value,omitempty"`
+}
+
+// matches reports whether r presents this credential.
+func (c *credential) matches(r *http.Request) bool {
+	switch c.Type {
+	case credentialTypeHeader:
+		return r.Header.Get(c.Header) == c.Value
+	case credentialTypeBasic:
+		username, password, ok := r.BasicAuth()
+		return ok && username == c.Username && password == c.Password
+	case credentialTypeQuery:
+		return r.URL.Query().Get(c.Param) == c.Value
+	case credentialTypeBearer:
+		fields := strings.Fields(r.Header.Get("Authorization"))
+		return len(fields) == 2 && strings.EqualFold(fields[0], "bearer") && fields[1] == c.Value
+	default:
+		return false
+	}
+}
+
+// matchesValue reports whether v is this credential presented as a bare
+// string, for transports with no HTTP request to carry a header, query
+// parameter or Authorization scheme (see stdioAuth). Basic credentials are
+// presented as "username:password".
+func (c *credential) matchesValue(v string) bool {
+	switch c.Type {
+	case credentialTypeHeader, credentialTypeQuery, credentialTypeBearer:
+		return v == c.Value
+	case credentialTypeBasic:
+		return v == c.Username+":"+c.Password
+	default:
+		return false
+	}
+}
+
+// validate checks that c has a known Type and the fields that Type needs.
+// An empty Value or Password would let a request that simply omits the
+// credential match it, so those are rejected too.
+func (c *credential) validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("credential of type %q has no name", c.Type)
+	}
+	switch c.Type {
+	case credentialTypeHeader:
+		if c.Header == "" || c.Value == "" {
+			return fmt.Errorf("header credential %q needs both header and value", c.Name)
+		}
+	case credentialTypeBasic:
+		if c.Username == "" || c.Password == "" {
+			return fmt.Errorf("basic credential %q needs both username and password", c.Name)
+		}
+	case credentialTypeQuery:
+		if c.Param == "" || c.Value == "" {
+			return fmt.Errorf("query credential %q needs both param and value", c.Name)
+		}
+	case credentialTypeBearer:
+		if c.Value == "" {
+			return fmt.Errorf("bearer credential %q needs a value", c.Name)
+		}
+	default:
+		return fmt.Errorf("credential %q has unknown type %q: valid types are %s, %s, %s, %s",
+			c.Name, c.Type, credentialTypeHeader, credentialTypeBasic, credentialTypeQuery, credentialTypeBearer)
+	}
+	return nil
+}
+
+// loadCredentials reads the JSON array of credentials at path (AUTH_FILE)
+// and validates each entry. Names must be unique, since whoami reporting an
+// ambiguous name would defeat the point of distinguishing tenants.
+func loadCredentials(path string) ([]credential, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is operator configuration, this is intentional
+	if err != nil {
+		return nil, err
+	}
+	var creds []credential
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	seen := map[string]bool{}
+	for i := range creds {
+		if err := creds[i].validate(); err != nil {
+			return nil, err
+		}
+		if seen[creds[i].Name] {
+			return nil, fmt.Errorf("duplicate credential name %q", creds[i].Name)
+		}
+		seen[creds[i].Name] = true
+	}
+	return creds, nil
+}
+
+// acceptedCredentials returns every credential checkAuth accepts: the
+// AUTH_HEADER/AUTH_VALUE pair, if set, followed by the AUTH_FILE entries.
+func acceptedCredentials() []credential {
+	var creds []credential
+	if authHeader != "" {
+		creds = append(creds, credential{
+			Name:   credentialNameDefault,
+			Type:   credentialTypeHeader,
+			Header: authHeader,
+			Value:  authValue,
+		})
+	}
+	return append(creds, authCredentials...)
+}
+
+// matchCredentialValue returns the first accepted credential that v
+// presents (see credential.matchesValue), or nil if none does.
+func matchCredentialValue(v string) *credential {
+	creds := acceptedCredentials()
+	for i := range creds {
+		if creds[i].matchesValue(v) {
+			return &creds[i]
+		}
+	}
+	return nil
+}
+
+type credentialKey struct{}
+
+// withCredential returns a copy of ctx carrying the credential that
+// authenticated the request. The SDK connects sessions with the context of
+// the request that created them (the SSE GET, or the streamable-http
+// initialize), so tools see the credential that opened their session.
+func withCredential(ctx context.Context, c *credential) context.Context {
+	return context.WithValue(ctx, credentialKey{}, c)
+}
+
+// credentialFromContext returns the credential stored by withCredential, or
+// nil if the session was not authenticated (auth disabled, or stdio without
+// STDIO_AUTH_META_KEY/STDIO_AUTH_ENV).
+func credentialFromContext(ctx context.Context) *credential {
+	c, _ := ctx.Value(credentialKey{}).(*credential)
+	return c
+}
+
+// WhoamiResponse represents the response from the whoami tool
+type WhoamiResponse struct {
+	Authenticated bool     `json:"authenticated"`
+	Credential    string   `json:"credential,omitempty"`
+	Type          string   `json:"type,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+}
+
+// whoamiHandler reports which accepted credential authenticated the
+// caller's session, and its bearer-token scopes: those of the current
+// request on streamable-http, or of the request that opened the session on
+// the other HTTP-based transports.
+func whoamiHandler(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, WhoamiResponse, error) {
+	var response WhoamiResponse
+	if c := credentialFromContext(ctx); c != nil {
+		response.Authenticated = true
+		response.Credential = c.Name
+		response.Type = c.Type
+	}
+	if req.Extra != nil && req.Extra.TokenInfo != nil {
+		response.Authenticated = true
+		response.Scopes = req.Extra.TokenInfo.Scopes
+	} else if tokenInfo := auth.TokenInfoFromContext(ctx); tokenInfo != nil {
+		// SSE and WebSocket requests carry no per-request token, but the
+		// one that opened the session is in its context.
+		response.Authenticated = true
+		response.Scopes = tokenInfo.Scopes
+	}
+	return nil, response, nil
+}
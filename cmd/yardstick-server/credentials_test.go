@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialMatches(t *testing.T) {
+	header := credential{Name: "h", Type: credentialTypeHeader, Header: "X-API-Key", Value: "k1"}
+	basic := credential{Name: "b", Type: credentialTypeBasic, Username: "alice", Password: "pw"}
+	query := credential{Name: "q", Type: credentialTypeQuery, Param: "api_key", Value: "k2"}
+	bearer := credential{Name: "t", Type: credentialTypeBearer, Value: "tok"}
+
+	tests := []struct {
+		name     string
+		cred     credential
+		url      string
+		setup    func(r *http.Request)
+		expected bool
+	}{
+		{name: "header match", cred: header, setup: func(r *http.Request) { r.Header.Set("X-API-Key", "k1") }, expected: true},
+		{name: "header mismatch", cred: header, setup: func(r *http.Request) { r.Header.Set("X-API-Key", "k2") }},
+		{name: "header missing", cred: header},
+		{name: "basic match", cred: basic, setup: func(r *http.Request) { r.SetBasicAuth("alice", "pw") }, expected: true},
+		{name: "basic wrong password", cred: basic, setup: func(r *http.Request) { r.SetBasicAuth("alice", "nope") }},
+		{name: "query match", cred: query, url: "/mcp?api_key=k2", expected: true},
+		{name: "query mismatch", cred: query, url: "/mcp?api_key=k1"},
+		{name: "bearer match", cred: bearer, setup: func(r *http.Request) { r.Header.Set("Authorization", "Bearer tok") }, expected: true},
+		{name: "bearer scheme case-insensitive", cred: bearer, setup: func(r *http.Request) { r.Header.Set("Authorization", "bearer tok") }, expected: true},
+		{name: "bearer is not basic", cred: bearer, setup: func(r *http.Request) { r.SetBasicAuth("tok", "tok") }},
+		{name: "unknown type never matches", cred: credential{Name: "x", Type: "magic"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := tt.url
+			if url == "" {
+				url = "/mcp"
+			}
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+			assert.Equal(t, tt.expected, tt.cred.matches(req))
+		})
+	}
+}
+
+func TestLoadCredentials(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "all types",
+			content: `[
+				{"name": "a", "type": "header", "header": "X-API-Key", "value": "k"},
+				{"name": "b", "type": "basic", "username": "u", "password": "p"},
+				{"name": "c", "type": "query", "param": "api_key", "value": "k"},
+				{"name": "d", "type": "bearer", "value": "t"}
+			]`,
+		},
+		{name: "malformed JSON", content: `[{"name": "a"`, wantErr: true},
+		{name: "missing name", content: `[{"type": "bearer", "value": "t"}]`, wantErr: true},
+		{name: "unknown type", content: `[{"name": "a", "type": "magic"}]`, wantErr: true},
+		{name: "empty value", content: `[{"name": "a", "type": "header", "header": "X-API-Key"}]`, wantErr: true},
+		{name: "duplicate name", content: `[{"name": "a", "type": "bearer", "value": "t"}, {"name": "a", "type": "bearer", "value": "u"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "auth.json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			creds, err := loadCredentials(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, creds, 4)
+		})
+	}
+
+	_, err := loadCredentials(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+// withCredentials installs the given AUTH_HEADER/AUTH_VALUE pair and
+// AUTH_FILE credentials for the duration of the test.
+func withCredentials(t *testing.T, header, value string, creds []credential) {
+	t.Helper()
+	origHeader, origValue, origCreds := authHeader, authValue, authCredentials
+	t.Cleanup(func() { authHeader, authValue, authCredentials = origHeader, origValue, origCreds })
+	authHeader, authValue, authCredentials = header, value, creds
+}
+
+func TestMatchCredential_MultipleCredentials(t *testing.T) {
+	withCredentials(t, "X-Auth-Token", "secret123", []credential{
+		{Name: "tenant-a", Type: credentialTypeHeader, Header: "X-API-Key", Value: "key-a"},
+		{Name: "tenant-b", Type: credentialTypeHeader, Header: "X-API-Key", Value: "key-b"},
+		{Name: "tenant-c", Type: credentialTypeBasic, Username: "carol", Password: "pw"},
+	})
+
+	tests := []struct {
+		name     string
+		setup    func(r *http.Request)
+		expected string
+		wantErr  bool
+	}{
+		{name: "AUTH_HEADER still accepted", setup: func(r *http.Request) { r.Header.Set("X-Auth-Token", "secret123") }, expected: "default"},
+		{name: "first tenant", setup: func(r *http.Request) { r.Header.Set("X-API-Key", "key-a") }, expected: "tenant-a"},
+		{name: "second tenant", setup: func(r *http.Request) { r.Header.Set("X-API-Key", "key-b") }, expected: "tenant-b"},
+		{name: "basic tenant", setup: func(r *http.Request) { r.SetBasicAuth("carol", "pw") }, expected: "tenant-c"},
+		{name: "unknown key", setup: func(r *http.Request) { r.Header.Set("X-API-Key", "key-z") }, wantErr: true},
+		{name: "no credential", setup: func(*http.Request) {}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			tt.setup(req)
+
+			c, err := matchCredential(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, checkAuth(req))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, c.Name)
+			assert.NoError(t, checkAuth(req))
+		})
+	}
+}
+
+func TestWhoamiHandler(t *testing.T) {
+	_, response, err := whoamiHandler(context.Background(), &mcp.CallToolRequest{}, struct{}{})
+	require.NoError(t, err)
+	assert.Equal(t, WhoamiResponse{}, response)
+
+	ctx := withCredential(context.Background(), &credential{Name: "tenant-a", Type: credentialTypeQuery})
+	req := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{Scopes: []string{"tools:echo"}}}}
+	_, response, err = whoamiHandler(ctx, req, struct{}{})
+	require.NoError(t, err)
+	assert.Equal(t, WhoamiResponse{
+		Authenticated: true,
+		Credential:    "tenant-a",
+		Type:          credentialTypeQuery,
+		Scopes:        []string{"tools:echo"},
+	}, response)
+}
+
+// headerRoundTripper adds a fixed header to every request, standing in for
+// a gateway that injects a tenant's credential.
+type headerRoundTripper struct {
+	key, value string
+}
+
+func (h headerRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set(h.key, h.value)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// TestWhoami_StreamableHTTP confirms the credential matched by authWrapper
+// actually reaches the tool through the SDK's session context, for each of
+// several tenants sharing one backend.
+func TestWhoami_StreamableHTTP(t *testing.T) {
+	withCredentials(t, "", "", []credential{
+		{Name: "tenant-a", Type: credentialTypeHeader, Header: "X-API-Key", Value: "key-a"},
+		{Name: "tenant-b", Type: credentialTypeHeader, Header: "X-API-Key", Value: "key-b"},
+	})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, whoamiHandler)
+	handler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server { return server }, nil)
+	ts := httptest.NewServer(authWrapper(handler))
+	defer ts.Close()
+
+	for _, tenant := range []struct{ key, name string }{{"key-a", "tenant-a"}, {"key-b", "tenant-b"}} {
+		t.Run(tenant.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, &mcp.StreamableClientTransport{
+				Endpoint:   ts.URL,
+				HTTPClient: &http.Client{Transport: headerRoundTripper{"X-API-Key", tenant.key}},
+			}, nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami"})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"authenticated": true, "credential": tenant.name, "type": "header"},
+				result.StructuredContent)
+		})
+	}
+}
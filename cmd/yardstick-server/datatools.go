@@ -0,0 +1,447 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+	_ "time/tzdata" // date_math must resolve IANA zones even in images without zoneinfo
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultFixedClock is the "now" of date_math unless FIXED_CLOCK says
+// otherwise. Tools never read the real clock, so their results are the
+// same on every run.
+const defaultFixedClock = "2025-01-01T00:00:00Z"
+
+const (
+	// maxIntOperand bounds math_int operands so that every operation but
+	// power fits in an int64 without overflow checks getting in the way.
+	maxIntOperand = 1_000_000_000
+	maxFloatDigit = 10
+	maxSortValues = 1000
+	maxLogicInput = 64
+	maxMergeInput = 16
+	maxDateOffset = 36500 // days, i.e. about a century either way
+)
+
+const (
+	opAdd      = "add"
+	opSubtract = "subtract"
+	opMultiply = "multiply"
+	opDivide   = "divide"
+	opModulo   = "modulo"
+	opPower    = "power"
+	opSqrt     = "sqrt"
+	opRound    = "round"
+
+	opAnd  = "and"
+	opOr   = "or"
+	opXor  = "xor"
+	opNot  = "not"
+	opNand = "nand"
+	opNor  = "nor"
+
+	sortAsc  = "asc"
+	sortDesc = "desc"
+
+	mergeShallow  = "shallow"
+	mergeDeep     = "deep"
+	conflictLast  = "last"
+	conflictFirst = "first"
+)
+
+// Schema-building helpers. The tools below declare their input schemas by
+// hand, like echo does, so that every constraint a host might coerce or
+// validate against (bounds, enums, defaults) is spelled out explicitly.
+
+func rawDefault(v any) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func enumProp(desc string, def string, values ...string) *jsonschema.Schema {
+	enum := make([]any, len(values))
+	for i, v := range values {
+		enum[i] = v
+	}
+	s := &jsonschema.Schema{Type: "string", Enum: enum, Description: desc}
+	if def != "" {
+		s.Default = rawDefault(def)
+	}
+	return s
+}
+
+func boundedProp(typ, desc string, minimum, maximum float64, def any) *jsonschema.Schema {
+	s := &jsonschema.Schema{Type: typ, Description: desc, Minimum: &minimum, Maximum: &maximum}
+	if def != nil {
+		s.Default = rawDefault(def)
+	}
+	return s
+}
+
+func arrayProp(desc string, items *jsonschema.Schema, minItems, maxItems int) *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "array", Description: desc, Items: items, MinItems: &minItems, MaxItems: &maxItems}
+}
+
+func objectSchema(required []string, props map[string]*jsonschema.Schema) *jsonschema.Schema {
+	return &jsonschema.Schema{Type: "object", Properties: props, Required: required}
+}
+
+// MathIntRequest represents the request for the math_int tool
+type MathIntRequest struct {
+	Op string `json:"op"`
+	A  int64  `json:"a"`
+	B  int64  `json:"b"`
+}
+
+// MathIntResponse represents the response from the math_int tool
+type MathIntResponse struct {
+	Result int64 `json:"result"`
+}
+
+func mathIntHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params MathIntRequest,
+) (*mcp.CallToolResult, MathIntResponse, error) {
+	a, b := params.A, params.B
+	var r int64
+	switch params.Op {
+	case opAdd:
+		r = a + b
+	case opSubtract:
+		r = a - b
+	case opMultiply:
+		r = a * b
+	case opDivide, opModulo:
+		if b == 0 {
+			return nil, MathIntResponse{}, errors.New("division by zero")
+		}
+		if params.Op == opDivide {
+			r = a / b // truncates toward zero
+		} else {
+			r = a % b // takes the sign of a
+		}
+	case opPower:
+		if b < 0 {
+			return nil, MathIntResponse{}, errors.New("power needs a non-negative exponent")
+		}
+		if math.Abs(math.Pow(float64(a), float64(b))) >= math.MaxInt64 {
+			return nil, MathIntResponse{}, fmt.Errorf("%d^%d overflows a 64-bit integer", a, b)
+		}
+		r = intPow(a, b)
+	default:
+		return nil, MathIntResponse{}, fmt.Errorf("unknown op %q", params.Op)
+	}
+	return nil, MathIntResponse{Result: r}, nil
+}
+
+// intPow returns a^b by squaring, so that large exponents of 0, 1 and -1
+// (the only bases that don't overflow with them) stay cheap.
+func intPow(a, b int64) int64 {
+	r := int64(1)
+	for ; b > 0; b >>= 1 {
+		if b&1 == 1 {
+			r *= a
+		}
+		a *= a
+	}
+	return r
+}
+
+// MathFloatRequest represents the request for the math_float tool
+type MathFloatRequest struct {
+	Op        string  `json:"op"`
+	A         float64 `json:"a"`
+	B         float64 `json:"b"`
+	Precision int     `json:"precision"`
+}
+
+// MathFloatResponse represents the response from the math_float tool
+type MathFloatResponse struct {
+	Result float64 `json:"result"`
+}
+
+func mathFloatHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params MathFloatRequest,
+) (*mcp.CallToolResult, MathFloatResponse, error) {
+	a, b := params.A, params.B
+	var r float64
+	switch params.Op {
+	case opAdd:
+		r = a + b
+	case opSubtract:
+		r = a - b
+	case opMultiply:
+		r = a * b
+	case opDivide:
+		if b == 0 {
+			return nil, MathFloatResponse{}, errors.New("division by zero")
+		}
+		r = a / b
+	case opPower:
+		r = math.Pow(a, b)
+	case opSqrt:
+		if a < 0 {
+			return nil, MathFloatResponse{}, errors.New("sqrt of a negative number")
+		}
+		r = math.Sqrt(a)
+	case opRound:
+		r = a
+	default:
+		return nil, MathFloatResponse{}, fmt.Errorf("unknown op %q", params.Op)
+	}
+	if math.IsInf(r, 0) || math.IsNaN(r) {
+		return nil, MathFloatResponse{}, errors.New("result is not a finite number")
+	}
+	// A result too large to scale has no digits after the point to round.
+	if scale := math.Pow10(params.Precision); !math.IsInf(r*scale, 0) {
+		r = math.Round(r*scale) / scale
+	}
+	return nil, MathFloatResponse{Result: r}, nil
+}
+
+// LogicRequest represents the request for the logic tool
+type LogicRequest struct {
+	Op     string `json:"op"`
+	Values []bool `json:"values"`
+}
+
+// LogicResponse represents the response from the logic tool
+type LogicResponse struct {
+	Result bool `json:"result"`
+}
+
+func logicHandler(_ context.Context, _ *mcp.CallToolRequest, params LogicRequest) (*mcp.CallToolResult, LogicResponse, error) {
+	values := params.Values
+	if len(values) == 0 {
+		return nil, LogicResponse{}, errors.New("values must not be empty")
+	}
+	trues := 0
+	for _, v := range values {
+		if v {
+			trues++
+		}
+	}
+	var r bool
+	switch params.Op {
+	case opAnd:
+		r = trues == len(values)
+	case opOr:
+		r = trues > 0
+	case opXor:
+		r = trues%2 == 1
+	case opNand:
+		r = trues != len(values)
+	case opNor:
+		r = trues == 0
+	case opNot:
+		if len(values) != 1 {
+			return nil, LogicResponse{}, fmt.Errorf("not takes exactly one value (got %d)", len(values))
+		}
+		r = !values[0]
+	default:
+		return nil, LogicResponse{}, fmt.Errorf("unknown op %q", params.Op)
+	}
+	return nil, LogicResponse{Result: r}, nil
+}
+
+// SortRequest represents the request for the sort_array tool
+type SortRequest struct {
+	Values []float64 `json:"values"`
+	Order  string    `json:"order"`
+	Unique bool      `json:"unique"`
+}
+
+// SortResponse represents the response from the sort_array tool
+type SortResponse struct {
+	Sorted []float64 `json:"sorted"`
+}
+
+func sortHandler(_ context.Context, _ *mcp.CallToolRequest, params SortRequest) (*mcp.CallToolResult, SortResponse, error) {
+	sorted := slices.Clone(params.Values)
+	slices.Sort(sorted)
+	if params.Unique {
+		sorted = slices.Compact(sorted)
+	}
+	if params.Order == sortDesc {
+		slices.Reverse(sorted)
+	}
+	if sorted == nil {
+		sorted = []float64{}
+	}
+	return nil, SortResponse{Sorted: sorted}, nil
+}
+
+// MergeRequest represents the request for the merge_objects tool
+type MergeRequest struct {
+	Objects  []map[string]any `json:"objects"`
+	Strategy string           `json:"strategy"`
+	Conflict string           `json:"conflict"`
+}
+
+// MergeResponse represents the response from the merge_objects tool
+type MergeResponse struct {
+	Merged map[string]any `json:"merged"`
+}
+
+func mergeHandler(_ context.Context, _ *mcp.CallToolRequest, params MergeRequest) (*mcp.CallToolResult, MergeResponse, error) {
+	objects := slices.Clone(params.Objects)
+	if params.Conflict == conflictFirst {
+		// Merging in reverse order lets the earliest object win.
+		slices.Reverse(objects)
+	}
+	merged := map[string]any{}
+	for _, obj := range objects {
+		mergeInto(merged, obj, params.Strategy == mergeDeep)
+	}
+	return nil, MergeResponse{Merged: merged}, nil
+}
+
+// mergeInto copies src's keys into dst, overwriting conflicts. With deep,
+// two objects under the same key are merged recursively instead.
+func mergeInto(dst, src map[string]any, deep bool) {
+	for k, v := range src {
+		if deep {
+			dstObj, dstOK := dst[k].(map[string]any)
+			srcObj, srcOK := v.(map[string]any)
+			if dstOK && srcOK {
+				nested := maps.Clone(dstObj)
+				mergeInto(nested, srcObj, true)
+				dst[k] = nested
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// DateRequest represents the request for the date_math tool
+type DateRequest struct {
+	Date       string `json:"date,omitempty"`
+	AddDays    int    `json:"add_days"`
+	AddHours   int    `json:"add_hours"`
+	AddMinutes int    `json:"add_minutes"`
+	Timezone   string `json:"timezone"`
+}
+
+// DateResponse represents the response from the date_math tool
+type DateResponse struct {
+	Result    string `json:"result"`
+	Unix      int64  `json:"unix"`
+	Weekday   string `json:"weekday"`
+	DayOfYear int    `json:"day_of_year"`
+	ISOWeek   int    `json:"iso_week"`
+}
+
+func dateHandler(_ context.Context, _ *mcp.CallToolRequest, params DateRequest) (*mcp.CallToolResult, DateResponse, error) {
+	t := fixedClock
+	if params.Date != "" {
+		var err error
+		if t, err = time.Parse(time.RFC3339, params.Date); err != nil {
+			return nil, DateResponse{}, fmt.Errorf("date must be RFC 3339 (e.g. %s): %w", defaultFixedClock, err)
+		}
+	}
+	loc, err := time.LoadLocation(params.Timezone)
+	if err != nil || params.Timezone == "Local" {
+		// "Local" is the host's zone, which would make results vary by host.
+		return nil, DateResponse{}, fmt.Errorf("unknown timezone %q", params.Timezone)
+	}
+	// Days are calendar days in the target timezone, so adding one across a
+	// DST change keeps the wall-clock time.
+	t = t.In(loc).AddDate(0, 0, params.AddDays).
+		Add(time.Duration(params.AddHours)*time.Hour + time.Duration(params.AddMinutes)*time.Minute)
+	_, week := t.ISOWeek()
+	return nil, DateResponse{
+		Result:    t.Format(time.RFC3339),
+		Unix:      t.Unix(),
+		Weekday:   t.Weekday().String(),
+		DayOfYear: t.YearDay(),
+		ISOWeek:   week,
+	}, nil
+}
+
+// addDataTools registers the deterministic math and data tools. Their
+// arguments are numbers, booleans, arrays and objects rather than strings,
+// with bounds, enums and defaults declared in their input schemas; the SDK
+// validates arguments against those schemas and fills in defaults before
+// the handlers run.
+func addDataTools(server *mcp.Server) {
+	maxInt := float64(maxIntOperand)
+	addTool(server, &mcp.Tool{
+		Name:        "math_int",
+		Description: "Integer arithmetic on two 64-bit integers. Division truncates toward zero.",
+		InputSchema: objectSchema([]string{"op", "a", "b"}, map[string]*jsonschema.Schema{
+			"op": enumProp("Operation to apply to a and b", "", opAdd, opSubtract, opMultiply, opDivide, opModulo, opPower),
+			"a":  boundedProp("integer", "First operand", -maxInt, maxInt, nil),
+			"b":  boundedProp("integer", "Second operand", -maxInt, maxInt, nil),
+		}),
+	}, mathIntHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "math_float",
+		Description: "Floating-point arithmetic, rounded half away from zero to the requested number of decimal places.",
+		InputSchema: objectSchema([]string{"op", "a"}, map[string]*jsonschema.Schema{
+			"op": enumProp("Operation to apply (sqrt and round use only a)", "",
+				opAdd, opSubtract, opMultiply, opDivide, opPower, opSqrt, opRound),
+			"a":         {Type: "number", Description: "First operand"},
+			"b":         {Type: "number", Description: "Second operand", Default: rawDefault(0)},
+			"precision": boundedProp("integer", "Decimal places to round the result to", 0, maxFloatDigit, 6),
+		}),
+	}, mathFloatHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "logic",
+		Description: "Boolean logic over a list of values. not takes exactly one value.",
+		InputSchema: objectSchema([]string{"op", "values"}, map[string]*jsonschema.Schema{
+			"op":     enumProp("Operation to apply", "", opAnd, opOr, opXor, opNot, opNand, opNor),
+			"values": arrayProp("Operands", &jsonschema.Schema{Type: "boolean"}, 1, maxLogicInput),
+		}),
+	}, logicHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "sort_array",
+		Description: "Sort a list of numbers.",
+		InputSchema: objectSchema([]string{"values"}, map[string]*jsonschema.Schema{
+			"values": arrayProp("Numbers to sort", &jsonschema.Schema{Type: "number"}, 0, maxSortValues),
+			"order":  enumProp("Sort order", sortAsc, sortAsc, sortDesc),
+			"unique": {Type: "boolean", Description: "Drop duplicate values", Default: rawDefault(false)},
+		}),
+	}, sortHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "merge_objects",
+		Description: "Merge a list of JSON objects into one.",
+		InputSchema: objectSchema([]string{"objects"}, map[string]*jsonschema.Schema{
+			"objects": arrayProp("Objects to merge, in order", &jsonschema.Schema{Type: "object"}, 1, maxMergeInput),
+			"strategy": enumProp("shallow replaces nested objects wholesale; deep merges them key by key",
+				mergeDeep, mergeShallow, mergeDeep),
+			"conflict": enumProp("Which object's value wins when a key is set in several", conflictLast,
+				conflictLast, conflictFirst),
+		}),
+	}, mergeHandler)
+
+	maxDays, maxHours, maxMinutes := float64(maxDateOffset), float64(maxDateOffset*24), float64(maxDateOffset*24*60)
+	addTool(server, &mcp.Tool{
+		Name: "date_math",
+		Description: "Add an offset to a date. With no date, starts from the server's fixed clock " +
+			"(FIXED_CLOCK, default " + defaultFixedClock + ") rather than the real time, so results never change.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"date": {Type: "string", Format: "date-time",
+				Description: "Starting point (RFC 3339); defaults to the server's fixed clock"},
+			"add_days":    boundedProp("integer", "Calendar days to add (may be negative)", -maxDays, maxDays, 0),
+			"add_hours":   boundedProp("integer", "Hours to add (may be negative)", -maxHours, maxHours, 0),
+			"add_minutes": boundedProp("integer", "Minutes to add (may be negative)", -maxMinutes, maxMinutes, 0),
+			"timezone": {Type: "string", Description: "IANA timezone for the result and for calendar days",
+				Default: rawDefault("UTC")},
+		}),
+	}, dateHandler)
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMathIntHandler(t *testing.T) {
+	tests := []struct {
+		op      string
+		a, b    int64
+		want    int64
+		wantErr string
+	}{
+		{op: opAdd, a: 2, b: 3, want: 5},
+		{op: opSubtract, a: 2, b: 3, want: -1},
+		{op: opMultiply, a: -4, b: 3, want: -12},
+		{op: opDivide, a: -7, b: 2, want: -3},
+		{op: opModulo, a: -7, b: 2, want: -1},
+		{op: opDivide, a: 1, b: 0, wantErr: "division by zero"},
+		{op: opModulo, a: 1, b: 0, wantErr: "division by zero"},
+		{op: opPower, a: 3, b: 39, want: 4052555153018976267},
+		{op: opPower, a: -1, b: maxIntOperand, want: 1},
+		{op: opPower, a: 2, b: 63, wantErr: "overflows"},
+		{op: opPower, a: 2, b: -1, wantErr: "non-negative"},
+		{op: "root", a: 2, b: 2, wantErr: "unknown op"},
+	}
+
+	for _, tt := range tests {
+		_, got, err := mathIntHandler(context.Background(), nil, MathIntRequest{Op: tt.op, A: tt.a, B: tt.b})
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, "%d %s %d", tt.a, tt.op, tt.b)
+			continue
+		}
+		require.NoError(t, err, "%d %s %d", tt.a, tt.op, tt.b)
+		assert.Equal(t, tt.want, got.Result, "%d %s %d", tt.a, tt.op, tt.b)
+	}
+}
+
+func TestMathFloatHandler(t *testing.T) {
+	tests := []struct {
+		op        string
+		a, b      float64
+		precision int
+		want      float64
+		wantErr   string
+	}{
+		{op: opAdd, a: 0.1, b: 0.2, precision: 6, want: 0.3},
+		{op: opDivide, a: 1, b: 3, precision: 2, want: 0.33},
+		{op: opDivide, a: 1, b: 0, precision: 2, wantErr: "division by zero"},
+		{op: opSqrt, a: 2, precision: 4, want: 1.4142},
+		{op: opSqrt, a: -1, precision: 4, wantErr: "negative"},
+		{op: opRound, a: 2.5, precision: 0, want: 3},
+		{op: opRound, a: -2.5, precision: 0, want: -3},
+		{op: opPower, a: 10, b: 400, precision: 0, wantErr: "not a finite number"},
+		{op: opAdd, a: 1e308, b: 0, precision: 6, want: 1e308},
+	}
+
+	for _, tt := range tests {
+		_, got, err := mathFloatHandler(context.Background(), nil,
+			MathFloatRequest{Op: tt.op, A: tt.a, B: tt.b, Precision: tt.precision})
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, "%g %s %g", tt.a, tt.op, tt.b)
+			continue
+		}
+		require.NoError(t, err, "%g %s %g", tt.a, tt.op, tt.b)
+		assert.InDelta(t, tt.want, got.Result, 1e-9, "%g %s %g", tt.a, tt.op, tt.b)
+	}
+}
+
+func TestLogicHandler(t *testing.T) {
+	tft := []bool{true, false, true}
+	tests := []struct {
+		op      string
+		values  []bool
+		want    bool
+		wantErr string
+	}{
+		{op: opAnd, values: tft, want: false},
+		{op: opAnd, values: []bool{true, true}, want: true},
+		{op: opOr, values: tft, want: true},
+		{op: opXor, values: tft, want: false},
+		{op: opXor, values: []bool{true, false, false}, want: true},
+		{op: opNand, values: tft, want: true},
+		{op: opNor, values: []bool{false, false}, want: true},
+		{op: opNot, values: []bool{false}, want: true},
+		{op: opNot, values: tft, wantErr: "exactly one"},
+		{op: opAnd, values: nil, wantErr: "must not be empty"},
+	}
+
+	for _, tt := range tests {
+		_, got, err := logicHandler(context.Background(), nil, LogicRequest{Op: tt.op, Values: tt.values})
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, "%s %v", tt.op, tt.values)
+			continue
+		}
+		require.NoError(t, err, "%s %v", tt.op, tt.values)
+		assert.Equal(t, tt.want, got.Result, "%s %v", tt.op, tt.values)
+	}
+}
+
+func TestSortHandler(t *testing.T) {
+	values := []float64{3, -1, 2.5, 3, 0}
+	tests := []struct {
+		name string
+		req  SortRequest
+		want []float64
+	}{
+		{name: "ascending", req: SortRequest{Values: values, Order: sortAsc}, want: []float64{-1, 0, 2.5, 3, 3}},
+		{name: "descending unique", req: SortRequest{Values: values, Order: sortDesc, Unique: true}, want: []float64{3, 2.5, 0, -1}},
+		{name: "empty", req: SortRequest{Order: sortAsc}, want: []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := sortHandler(context.Background(), nil, tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Sorted)
+		})
+	}
+	assert.Equal(t, []float64{3, -1, 2.5, 3, 0}, values, "the input must not be modified")
+}
+
+func TestMergeHandler(t *testing.T) {
+	objects := []map[string]any{
+		{"a": 1, "nested": map[string]any{"x": 1, "y": 1}},
+		{"b": 2, "nested": map[string]any{"y": 2}},
+	}
+	tests := []struct {
+		name     string
+		strategy string
+		conflict string
+		want     map[string]any
+	}{
+		{
+			name: "deep, last wins", strategy: mergeDeep, conflict: conflictLast,
+			want: map[string]any{"a": 1, "b": 2, "nested": map[string]any{"x": 1, "y": 2}},
+		},
+		{
+			name: "deep, first wins", strategy: mergeDeep, conflict: conflictFirst,
+			want: map[string]any{"a": 1, "b": 2, "nested": map[string]any{"x": 1, "y": 1}},
+		},
+		{
+			name: "shallow, last wins", strategy: mergeShallow, conflict: conflictLast,
+			want: map[string]any{"a": 1, "b": 2, "nested": map[string]any{"y": 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := mergeHandler(context.Background(), nil,
+				MergeRequest{Objects: objects, Strategy: tt.strategy, Conflict: tt.conflict})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.Merged)
+		})
+	}
+	assert.Equal(t, map[string]any{"x": 1, "y": 1}, objects[0]["nested"], "the input must not be modified")
+}
+
+func TestDateHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     DateRequest
+		want    DateResponse
+		wantErr string
+	}{
+		{
+			name: "fixed clock",
+			req:  DateRequest{Timezone: "UTC"},
+			want: DateResponse{Result: "2025-01-01T00:00:00Z", Unix: 1735689600, Weekday: "Wednesday", DayOfYear: 1, ISOWeek: 1},
+		},
+		{
+			name: "offsets across a leap day",
+			req:  DateRequest{Date: "2024-02-28T12:00:00Z", AddDays: 1, AddHours: 13, AddMinutes: -30, Timezone: "UTC"},
+			want: DateResponse{Result: "2024-03-01T00:30:00Z", Unix: 1709253000, Weekday: "Friday", DayOfYear: 61, ISOWeek: 9},
+		},
+		{
+			name: "calendar day across a DST change",
+			req:  DateRequest{Date: "2025-03-08T12:00:00-05:00", AddDays: 1, Timezone: "America/New_York"},
+			want: DateResponse{Result: "2025-03-09T12:00:00-04:00", Unix: 1741536000, Weekday: "Sunday", DayOfYear: 68, ISOWeek: 10},
+		},
+		{name: "bad date", req: DateRequest{Date: "yesterday", Timezone: "UTC"}, wantErr: "RFC 3339"},
+		{name: "bad timezone", req: DateRequest{Timezone: "Mars/Olympus"}, wantErr: "unknown timezone"},
+		{name: "host timezone", req: DateRequest{Timezone: "Local"}, wantErr: "unknown timezone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := dateHandler(context.Background(), nil, tt.req)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestDataTools_SchemaValidation checks that the declared schemas are
+// enforced and their defaults applied, end to end through the SDK.
+func TestDataTools_SchemaValidation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session := connectInMemory(ctx, t, nil, addDataTools, nil)
+
+	tests := []struct {
+		name    string
+		tool    string
+		args    map[string]any
+		want    map[string]any
+		wantErr bool
+	}{
+		{name: "precision defaults to 6", tool: "math_float", args: map[string]any{"op": opDivide, "a": 2, "b": 3},
+			want: map[string]any{"result": 0.666667}},
+		{name: "order defaults to asc", tool: "sort_array", args: map[string]any{"values": []any{2, 1}},
+			want: map[string]any{"sorted": []any{1.0, 2.0}}},
+		{name: "date defaults to the fixed clock", tool: "date_math", args: map[string]any{"add_days": 1},
+			want: map[string]any{"result": "2025-01-02T00:00:00Z"}},
+		{name: "float where an integer is required", tool: "math_int",
+			args: map[string]any{"op": opAdd, "a": 1.5, "b": 1}, wantErr: true},
+		{name: "operand above maximum", tool: "math_int",
+			args: map[string]any{"op": opAdd, "a": maxIntOperand + 1, "b": 1}, wantErr: true},
+		{name: "op outside enum", tool: "logic", args: map[string]any{"op": "implies", "values": []any{true}}, wantErr: true},
+		{name: "string where a boolean is required", tool: "logic",
+			args: map[string]any{"op": opAnd, "values": []any{"true"}}, wantErr: true},
+		{name: "too few objects", tool: "merge_objects", args: map[string]any{"objects": []any{}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: tt.tool, Arguments: tt.args})
+			require.NoError(t, err)
+			if tt.wantErr {
+				assert.True(t, result.IsError)
+				return
+			}
+			require.False(t, result.IsError, "%v", result.Content)
+			got := result.StructuredContent.(map[string]any)
+			for k, v := range tt.want {
+				assert.Equal(t, v, got[k])
+			}
+		})
+	}
+}
+
+func TestIntPow(t *testing.T) {
+	for a := int64(-3); a <= 3; a++ {
+		for b := int64(0); b <= 20; b++ {
+			assert.Equal(t, int64(math.Pow(float64(a), float64(b))), intPow(a, b), "%d^%d", a, b)
+		}
+	}
+}
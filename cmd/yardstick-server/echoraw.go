@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// EchoRawRequest represents the request for the echo_raw tool
+type EchoRawRequest struct {
+	Input string `json:"input"`
+}
+
+// EchoRawResponse represents the response from the echo_raw tool. The
+// counts describe the string as the server received it, so comparing them
+// with what the client sent locates corruption on the way in, and
+// comparing Output with the input locates it on the way back.
+type EchoRawResponse struct {
+	Output string `json:"output"`
+	// Bytes is the length of the UTF-8 encoding of Output.
+	Bytes int `json:"bytes"`
+	// CodePoints is the number of Unicode code points in Output.
+	CodePoints int `json:"code_points"`
+	// ReplacementChars counts U+FFFD, which is what invalid UTF-8 turns into
+	// when something along the way decodes it leniently.
+	ReplacementChars int `json:"replacement_chars"`
+	// ControlChars counts C0/C1 control characters, including newlines and
+	// tabs, which proxies sometimes strip or escape.
+	ControlChars int `json:"control_chars"`
+	// SHA256 is the hex digest of Output's UTF-8 bytes.
+	SHA256 string `json:"sha256"`
+}
+
+func echoRawHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params EchoRawRequest,
+) (*mcp.CallToolResult, EchoRawResponse, error) {
+	sum := sha256.Sum256([]byte(params.Input))
+	controls := 0
+	for _, r := range params.Input {
+		if unicode.IsControl(r) {
+			controls++
+		}
+	}
+	return nil, EchoRawResponse{
+		Output:           params.Input,
+		Bytes:            len(params.Input),
+		CodePoints:       utf8.RuneCountInString(params.Input),
+		ReplacementChars: strings.Count(params.Input, string(utf8.RuneError)),
+		ControlChars:     controls,
+		SHA256:           hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// addEchoRawTool registers echo_raw, which unlike echo accepts any string
+// at all: multi-byte UTF-8, emoji, right-to-left text, control characters,
+// embedded newlines, and strings of any length.
+func addEchoRawTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "echo_raw",
+		Description: "Echo back any string unchanged, with its UTF-8 byte length, code-point count and SHA-256, " +
+			"for detecting encoding corruption between client and server.",
+		InputSchema: objectSchema([]string{"input"}, map[string]*jsonschema.Schema{
+			"input": {Type: "string", Description: "Arbitrary string to echo back"},
+		}),
+	}, echoRawHandler)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEchoRawHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		bytes, runes int
+		replacement  int
+		controls     int
+	}{
+		{name: "empty", input: ""},
+		{name: "ascii", input: "abc123", bytes: 6, runes: 6},
+		{name: "multi-byte", input: "héllo wörld", bytes: 13, runes: 11},
+		{name: "emoji with ZWJ", input: "👩‍💻", bytes: 11, runes: 3},
+		{name: "right-to-left", input: "שלום مرحبا", bytes: 19, runes: 10},
+		{name: "control characters", input: "a\tb\nc\r\n\x00", bytes: 8, runes: 8, controls: 5},
+		{name: "replacement character", input: "x�y", bytes: 5, runes: 3, replacement: 1},
+		{name: "long", input: strings.Repeat("é", 10000), bytes: 20000, runes: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := echoRawHandler(context.Background(), nil, EchoRawRequest{Input: tt.input})
+			require.NoError(t, err)
+			assert.Equal(t, tt.input, got.Output)
+			assert.Equal(t, tt.bytes, got.Bytes)
+			assert.Equal(t, tt.runes, got.CodePoints)
+			assert.Equal(t, tt.replacement, got.ReplacementChars)
+			assert.Equal(t, tt.controls, got.ControlChars)
+			assert.Len(t, got.SHA256, 64)
+		})
+	}
+
+	_, got, err := echoRawHandler(context.Background(), nil, EchoRawRequest{Input: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", got.SHA256)
+}
+
+// TestEchoRaw_RoundTrip sends strings that the echo tool rejects through
+// the SDK and checks they come back unchanged.
+func TestEchoRaw_RoundTrip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session := connectInMemory(ctx, t, nil, addEchoRawTool, nil)
+
+	for _, input := range []string{"", "日本語 🎉", "line one\nline two\t\"quoted\" \\ </script>", "‮evil‬"} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "echo_raw",
+			Arguments: map[string]any{"input": input},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "%v", result.Content)
+		got := result.StructuredContent.(map[string]any)
+		assert.Equal(t, input, got["output"])
+		assert.Equal(t, float64(len(input)), got["bytes"])
+	}
+}
+
+func TestEchoHandler_CustomPattern(t *testing.T) {
+	origPattern, origRegex := echoPattern, echoRegex
+	origArgs := os.Args
+	defer func() {
+		echoPattern, echoRegex = origPattern, origRegex
+		os.Args = origArgs
+	}()
+
+	withFreshFlagSet(t)
+	os.Args = []string{"yardstick-server"}
+	t.Setenv("ECHO_PATTERN", `^[a-z ]+$`)
+
+	parseConfig()
+
+	assert.Equal(t, `^[a-z ]+$`, echoPattern)
+	assert.True(t, validateEchoInput("hello world"))
+	assert.False(t, validateEchoInput("Hello1"))
+	_, resp, err := echoHandler(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}},
+		EchoRequest{Input: "hello world"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", resp.Output)
+
+	result, _, err := echoHandler(context.Background(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}},
+		EchoRequest{Input: "Hello1"})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "ECHO_PATTERN")
+}
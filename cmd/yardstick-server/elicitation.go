@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultElicitMessage = "Please fill in the yardstick form."
+	defaultElicitTimeout = 60000
+	maxElicitTimeout     = 600000
+
+	// elicitInputID is the ID of the elicitation request in a 2026-07-28
+	// input-required result.
+	elicitInputID = "elicit"
+
+	elicitAccept  = "accept"
+	elicitDecline = "decline"
+	elicitCancel  = "cancel"
+)
+
+// elicitForm is the schema elicit asks the client to fill in: one
+// property of each kind the spec allows in a form, each with the
+// constraints a client might enforce, or drop.
+func elicitForm() *jsonschema.Schema {
+	minName, maxName := 1, 64
+	minAge, maxAge := 0.0, 150.0
+	color := enumProp("Your favourite color", "", "red", "green", "blue")
+	color.Title = "Color"
+	return &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"name", "color"},
+		Properties: map[string]*jsonschema.Schema{
+			"name": {
+				Type: "string", Title: "Name", Description: "Your name",
+				MinLength: &minName, MaxLength: &maxName,
+			},
+			"email": {Type: "string", Title: "Email", Description: "Your email address", Format: "email"},
+			"age": {
+				Type: "integer", Title: "Age", Description: "Your age in years",
+				Minimum: &minAge, Maximum: &maxAge,
+			},
+			"subscribe": {
+				Type: "boolean", Title: "Subscribe", Description: "Whether to subscribe to updates",
+				Default: rawDefault(false),
+			},
+			"color": color,
+		},
+	}
+}
+
+// ElicitRequest represents the request for the elicit tool
+type ElicitRequest struct {
+	Message   string `json:"message,omitempty"`
+	TimeoutMS int    `json:"timeout_ms,omitempty"`
+}
+
+// ElicitResponse represents the response from the elicit tool: what the
+// user did with the form.
+type ElicitResponse struct {
+	// Action is accept, decline or cancel.
+	Action string `json:"action"`
+	// Content is the form as submitted, only if it was accepted.
+	Content map[string]any `json:"content,omitempty"`
+	// RoundTrip and ElapsedMS are as in SampleResponse.
+	RoundTrip string `json:"round_trip"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+}
+
+// elicitResponse turns the client's answer into the tool's response,
+// checking an accepted form against elicitForm and filling in its
+// defaults. The SDK does the same, but only when it sends the request
+// itself.
+func elicitResponse(result *mcp.ElicitResult, roundTrip string, elapsed time.Duration) (ElicitResponse, error) {
+	switch result.Action {
+	case elicitAccept:
+		resolved, err := elicitForm().Resolve(nil)
+		if err != nil {
+			return ElicitResponse{}, err
+		}
+		if err := resolved.Validate(result.Content); err != nil {
+			return ElicitResponse{}, fmt.Errorf("accepted form does not match the requested schema: %w", err)
+		}
+		if err := resolved.ApplyDefaults(&result.Content); err != nil {
+			return ElicitResponse{}, err
+		}
+	case elicitDecline, elicitCancel:
+	default:
+		return ElicitResponse{}, fmt.Errorf("unknown elicitation action %q: valid values are %s, %s, %s",
+			result.Action, elicitAccept, elicitDecline, elicitCancel)
+	}
+	return ElicitResponse{
+		Action:    result.Action,
+		Content:   result.Content,
+		RoundTrip: roundTrip,
+		ElapsedMS: elapsed.Milliseconds(),
+	}, nil
+}
+
+// elicitHandler asks the user, through the client, to fill in elicitForm:
+// with an input request if the client uses them, otherwise with
+// elicitation/create.
+func elicitHandler(
+	ctx context.Context, req *mcp.CallToolRequest, params ElicitRequest,
+) (*mcp.CallToolResult, ElicitResponse, error) {
+	if params.Message == "" {
+		params.Message = defaultElicitMessage
+	}
+	if params.TimeoutMS == 0 {
+		params.TimeoutMS = defaultElicitTimeout
+	}
+	if params.TimeoutMS < 1 || params.TimeoutMS > maxElicitTimeout {
+		return nil, ElicitResponse{}, fmt.Errorf("timeout_ms must be between 1 and %d (got %d)",
+			maxElicitTimeout, params.TimeoutMS)
+	}
+	if caps := req.ClientCapabilities(); caps == nil || caps.Elicitation == nil {
+		return nil, ElicitResponse{}, errors.New(
+			"client does not support elicitation: it did not declare the elicitation capability")
+	}
+	elicitParams := &mcp.ElicitParams{Mode: "form", Message: params.Message, RequestedSchema: elicitForm()}
+
+	if !usesInputRequests(req) {
+		response, err := elicitByRequest(ctx, req.Session, elicitParams, params.TimeoutMS)
+		return nil, response, err
+	}
+	return elicitByInputRequest(req, elicitParams, params.TimeoutMS)
+}
+
+// elicitByRequest sends elicitation/create and waits at most timeoutMS for
+// the answer, cancelling the request if it doesn't come.
+func elicitByRequest(
+	ctx context.Context, session *mcp.ServerSession, params *mcp.ElicitParams, timeoutMS int,
+) (ElicitResponse, error) {
+	elicitCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMS)*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := session.Elicit(elicitCtx, params)
+	elapsed := time.Since(start)
+	switch {
+	case err != nil && ctx.Err() == nil && elicitCtx.Err() != nil:
+		return ElicitResponse{}, fmt.Errorf("client did not answer elicitation/create within %dms", timeoutMS)
+	case err != nil:
+		return ElicitResponse{}, fmt.Errorf("elicitation/create failed: %w", err)
+	}
+	return elicitResponse(result, roundTripRequest, elapsed)
+}
+
+// elicitByInputRequest returns the elicitation request as an input
+// request. The client answers by calling the tool again with the answer.
+func elicitByInputRequest(
+	req *mcp.CallToolRequest, params *mcp.ElicitParams, timeoutMS int,
+) (*mcp.CallToolResult, ElicitResponse, error) {
+	answer, ok := req.Params.InputResponses[elicitInputID]
+	if !ok {
+		return &mcp.CallToolResult{
+			InputRequests: mcp.InputRequestMap{elicitInputID: params},
+			RequestState:  inputRequestState(),
+		}, ElicitResponse{}, nil
+	}
+	result, ok := answer.(*mcp.ElicitResult)
+	if !ok {
+		return nil, ElicitResponse{}, fmt.Errorf("input response %q is %T, not an elicitation result", elicitInputID, answer)
+	}
+	elapsed, err := inputElapsed(req, timeoutMS)
+	if err != nil {
+		return nil, ElicitResponse{}, err
+	}
+	response, err := elicitResponse(result, roundTripInputRequired, elapsed)
+	return nil, response, err
+}
+
+// addElicitationTool registers elicit, which asks the user to fill in a
+// form and reports what they did.
+func addElicitationTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "elicit",
+		Description: "Ask the user, through the client, to fill in a form with a name, email, age, subscribe flag " +
+			"and color, and report whether they accepted (with the form's content), declined or cancelled. " +
+			"The client must declare the elicitation capability.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"message": {Type: "string", Default: rawDefault(defaultElicitMessage), Description: "Message shown with the form"},
+			"timeout_ms": boundedProp("integer", "Milliseconds to wait for the user's answer", 1, maxElicitTimeout,
+				defaultElicitTimeout),
+		}),
+	}, elicitHandler)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElicitResponse(t *testing.T) {
+	got, err := elicitResponse(&mcp.ElicitResult{Action: elicitAccept, Content: map[string]any{
+		"name": "Ada", "color": "green", "age": 36,
+	}}, roundTripRequest, 2*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, ElicitResponse{
+		Action:    elicitAccept,
+		Content:   map[string]any{"name": "Ada", "color": "green", "age": 36, "subscribe": false},
+		RoundTrip: roundTripRequest,
+		ElapsedMS: 2,
+	}, got)
+
+	got, err = elicitResponse(&mcp.ElicitResult{Action: elicitDecline}, roundTripInputRequired, 0)
+	require.NoError(t, err)
+	assert.Equal(t, ElicitResponse{Action: elicitDecline, RoundTrip: roundTripInputRequired}, got)
+
+	tests := []struct {
+		result  *mcp.ElicitResult
+		wantErr string
+	}{
+		{&mcp.ElicitResult{Action: elicitAccept, Content: map[string]any{"name": "Ada"}}, "does not match"},
+		{&mcp.ElicitResult{Action: elicitAccept, Content: map[string]any{"name": "Ada", "color": "pink"}}, "does not match"},
+		{&mcp.ElicitResult{Action: elicitAccept, Content: map[string]any{"name": "", "color": "red"}}, "does not match"},
+		{&mcp.ElicitResult{Action: elicitAccept, Content: map[string]any{
+			"name": "Ada", "color": "red", "age": 151,
+		}}, "does not match"},
+		{&mcp.ElicitResult{Action: "ignore"}, `unknown elicitation action "ignore"`},
+	}
+	for _, tt := range tests {
+		_, err := elicitResponse(tt.result, roundTripRequest, 0)
+		assert.ErrorContains(t, err, tt.wantErr, "%+v", tt.result)
+	}
+}
+
+func connectElicitation(ctx context.Context, t *testing.T, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	return connectInMemory(ctx, t, nil, addElicitationTool, opts)
+}
+
+// TestElicit_InputRequired elicits over a 2026-07-28 session, where the
+// SDK's client answers the input request and calls the tool again.
+func TestElicit_InputRequired(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, action := range []string{elicitAccept, elicitDecline, elicitCancel} {
+		t.Run(action, func(t *testing.T) {
+			var message string
+			session := connectElicitation(ctx, t, &mcp.ClientOptions{
+				ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+					message = req.Params.Message
+					result := &mcp.ElicitResult{Action: action}
+					if action == elicitAccept {
+						result.Content = map[string]any{"name": "Ada", "color": "blue", "subscribe": true}
+					}
+					return result, nil
+				},
+			})
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "elicit", Arguments: map[string]any{
+				"message": "Who are you?",
+			}})
+			require.NoError(t, err)
+			require.False(t, result.IsError, "%v", result.Content)
+			assert.Equal(t, "Who are you?", message)
+			var response ElicitResponse
+			require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+			assert.Equal(t, action, response.Action)
+			assert.Equal(t, roundTripInputRequired, response.RoundTrip)
+			if action == elicitAccept {
+				assert.Equal(t, map[string]any{"name": "Ada", "color": "blue", "subscribe": true}, response.Content)
+			} else {
+				assert.Empty(t, response.Content)
+			}
+		})
+	}
+
+	t.Run("no capability", func(t *testing.T) {
+		session := connectElicitation(ctx, t, nil)
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "elicit", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "client does not support elicitation")
+	})
+}
+
+// TestElicit_Request elicits over a 2025-11-25 session, which the server
+// sends elicitation/create.
+func TestElicit_Request(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addElicitationTool(server)
+
+	call := func(t *testing.T, answer func(req *jsonrpc.Request) any, args map[string]any) mcp.CallToolResult {
+		t.Helper()
+		c := newLegacyConnWithCapabilities(ctx, t, server, map[string]any{"elicitation": map[string]any{}})
+		c.answer = answer
+		resp, _ := c.call(ctx, "tools/call", map[string]any{"name": "elicit", "arguments": args})
+		require.NoError(t, resp.Error)
+		var result mcp.CallToolResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		return result
+	}
+
+	var params mcp.ElicitParams
+	result := call(t, func(req *jsonrpc.Request) any {
+		assert.Equal(t, "elicitation/create", req.Method)
+		require.NoError(t, json.Unmarshal(req.Params, &params))
+		return map[string]any{"action": "accept", "content": map[string]any{"name": "Ada", "color": "red"}}
+	}, map[string]any{})
+	require.False(t, result.IsError, "%v", result.Content)
+	assert.Equal(t, defaultElicitMessage, params.Message)
+	assert.Equal(t, "form", params.Mode)
+	assert.JSONEq(t, mustJSON(t, elicitForm()), mustJSON(t, params.RequestedSchema))
+	var response ElicitResponse
+	require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+	assert.Equal(t, ElicitResponse{
+		Action:    elicitAccept,
+		Content:   map[string]any{"name": "Ada", "color": "red", "subscribe": false},
+		RoundTrip: roundTripRequest,
+		ElapsedMS: response.ElapsedMS,
+	}, response)
+
+	result = call(t, func(*jsonrpc.Request) any {
+		return map[string]any{"action": "accept", "content": map[string]any{"name": "Ada"}}
+	}, map[string]any{})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "does not match requested schema")
+
+	result = call(t, func(*jsonrpc.Request) any { return nil }, map[string]any{"timeout_ms": 20})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "did not answer elicitation/create within 20ms")
+}
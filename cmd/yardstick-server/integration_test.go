@@ -197,9 +197,9 @@ func TestServerStartup(t *testing.T) {
 			// test the actual server startup in a unit test, we'll verify the
 			// configuration is valid
 
-			// Verify alphanumeric validation works
-			assert.True(t, validateAlphanumeric("test123"))
-			assert.False(t, validateAlphanumeric("test@123"))
+			// Verify echo input validation works
+			assert.True(t, validateEchoInput("test123"))
+			assert.False(t, validateEchoInput("test@123"))
 
 			// Verify echo handler works
 			req := &mcp.CallToolRequest{}
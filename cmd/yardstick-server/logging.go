@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	methodSetLevel = "logging/setLevel"
+
+	defaultLogRepeat = 1
+	maxLogRepeat     = 100
+	defaultLogger    = "yardstick"
+
+	// protocolVersionModern is the MCP protocol version that replaced
+	// logging/setLevel with a level in each request's _meta. Version
+	// strings are ISO dates, so they compare chronologically.
+	protocolVersionModern = "2026-07-28"
+)
+
+// logLevels are the RFC 5424 severities MCP uses, least severe first. This
+// is the order log_sequence emits them in.
+var logLevels = []mcp.LoggingLevel{
+	"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency",
+}
+
+// LogSequenceRequest represents the request for the log_sequence tool
+type LogSequenceRequest struct {
+	Repeat     int    `json:"repeat,omitempty"`
+	Logger     string `json:"logger,omitempty"`
+	Structured bool   `json:"structured,omitempty"`
+}
+
+// LogSequenceResponse represents the response from the log_sequence tool
+type LogSequenceResponse struct {
+	// Emitted is how many messages the tool produced: one per level, per
+	// repeat.
+	Emitted int `json:"emitted"`
+	// Sent is how many of them passed the session's level and were sent.
+	Sent int `json:"sent"`
+	// Level is the minimum level in effect for the call, empty if the
+	// client never set one, in which case nothing is sent.
+	Level mcp.LoggingLevel `json:"level,omitempty"`
+	// SentLevels are the levels that passed, least severe first.
+	SentLevels []mcp.LoggingLevel `json:"sent_levels"`
+}
+
+// logLevelState remembers the level each session last set with
+// logging/setLevel, so log_sequence can report which of its messages the
+// SDK will drop. Sessions on the 2026-07-28 protocol send the level with
+// each request instead, in _meta.
+type logLevelState struct {
+	server *mcp.Server
+
+	mu     sync.Mutex
+	levels map[mcp.Session]mcp.LoggingLevel
+}
+
+// middleware rejects levels outside RFC 5424, which the SDK would
+// otherwise accept and treat as debug, and records the rest. Levels of
+// sessions that have ended are dropped as each new one is recorded.
+func (s *logLevelState) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != methodSetLevel {
+			return next(ctx, method, req)
+		}
+		params, ok := req.GetParams().(*mcp.SetLoggingLevelParams)
+		if !ok || params == nil {
+			return next(ctx, method, req)
+		}
+		if !slices.Contains(logLevels, params.Level) {
+			return nil, &jsonrpc.Error{
+				Code:    jsonrpc.CodeInvalidParams,
+				Message: fmt.Sprintf("unknown log level %q: must be one of %v", params.Level, logLevels),
+			}
+		}
+		result, err := next(ctx, method, req)
+		if err == nil {
+			connected := connectedSessions(s.server)
+			s.mu.Lock()
+			maps.DeleteFunc(s.levels, func(session mcp.Session, _ mcp.LoggingLevel) bool { return !connected[session] })
+			s.levels[req.GetSession()] = params.Level
+			s.mu.Unlock()
+		}
+		return result, err
+	}
+}
+
+// level returns the minimum level in effect for req: on the 2026-07-28
+// protocol the one in its _meta, otherwise the one its session set.
+func (s *logLevelState) level(req *mcp.CallToolRequest) mcp.LoggingLevel {
+	if params := req.Session.InitializeParams(); params != nil && params.ProtocolVersion >= protocolVersionModern {
+		level, _ := req.Params.Meta[mcp.MetaKeyLogLevel].(string)
+		return mcp.LoggingLevel(level)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.levels[req.Session]
+}
+
+// passes reports whether a message at level is sent to a session whose
+// minimum level is minimum, as the SDK decides it.
+func passes(level, minimum mcp.LoggingLevel) bool {
+	if minimum == "" {
+		return false
+	}
+	return slices.Index(logLevels, level) >= max(slices.Index(logLevels, minimum), 0)
+}
+
+func (s *logLevelState) sequenceHandler(
+	ctx context.Context, req *mcp.CallToolRequest, params LogSequenceRequest,
+) (*mcp.CallToolResult, LogSequenceResponse, error) {
+	repeat := params.Repeat
+	if repeat == 0 {
+		repeat = defaultLogRepeat
+	}
+	if repeat < 1 || repeat > maxLogRepeat {
+		return nil, LogSequenceResponse{}, fmt.Errorf("repeat must be between 1 and %d (got %d)", maxLogRepeat, repeat)
+	}
+	logger := params.Logger
+	if logger == "" {
+		logger = defaultLogger
+	}
+
+	response := LogSequenceResponse{Level: s.level(req), SentLevels: []mcp.LoggingLevel{}}
+	for _, level := range logLevels {
+		if passes(level, response.Level) {
+			response.SentLevels = append(response.SentLevels, level)
+		}
+	}
+	for round := range repeat {
+		for i, level := range logLevels {
+			sequence := round*len(logLevels) + i + 1
+			text := fmt.Sprintf("log message %d at level %s", sequence, level)
+			var data any = text
+			if params.Structured {
+				data = map[string]any{"sequence": sequence, "level": level, "text": text}
+			}
+			if err := req.Session.Log(ctx, &mcp.LoggingMessageParams{Level: level, Logger: logger, Data: data}); err != nil {
+				return nil, LogSequenceResponse{}, fmt.Errorf("failed to send log message %d: %w", sequence, err)
+			}
+			response.Emitted++
+			if passes(level, response.Level) {
+				response.Sent++
+			}
+		}
+	}
+	return nil, response, nil
+}
+
+// addLoggingTools registers log_sequence and the logging/setLevel
+// middleware it relies on. The SDK itself advertises the logging
+// capability, answers logging/setLevel and filters messages by level.
+func addLoggingTools(server *mcp.Server) {
+	state := &logLevelState{server: server, levels: map[mcp.Session]mcp.LoggingLevel{}}
+	server.AddReceivingMiddleware(state.middleware)
+
+	addTool(server, &mcp.Tool{
+		Name: "log_sequence",
+		Description: "Send a notifications/message at every log level, debug through emergency, in order, " +
+			"repeat times. Messages below the session's level (set with logging/setLevel) are not sent.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"repeat": boundedProp("integer", "How many times to run through the levels", 1, maxLogRepeat,
+				defaultLogRepeat),
+			"logger": {Type: "string", Default: rawDefault(defaultLogger), Description: "Logger name to send"},
+			"structured": {Type: "boolean", Default: rawDefault(false),
+				Description: "Send each message's data as an object instead of a string"},
+		}),
+	}, state.sequenceHandler)
+}
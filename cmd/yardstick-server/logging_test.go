@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasses(t *testing.T) {
+	assert.False(t, passes("emergency", ""))
+	assert.True(t, passes("debug", "debug"))
+	assert.False(t, passes("notice", "warning"))
+	assert.True(t, passes("warning", "warning"))
+	assert.True(t, passes("alert", "error"))
+	// The SDK treats an unknown minimum as debug.
+	assert.True(t, passes("debug", "verbose"))
+}
+
+// legacyConn speaks raw JSON-RPC to a server as a client on the
+// 2025-11-25 protocol, the last to use logging/setLevel, which the SDK's
+// own client no longer negotiates.
+type legacyConn struct {
+	t      *testing.T
+	conn   mcp.Connection
+	nextID int64
+	// answer, if set, answers the requests the server sends while call
+	// waits for its response, with the result it returns, or not at all if
+	// that is nil.
+	answer func(req *jsonrpc.Request) any
+}
+
+func newLegacyConn(ctx context.Context, t *testing.T, server *mcp.Server) *legacyConn {
+	t.Helper()
+	return newLegacyConnWithCapabilities(ctx, t, server, map[string]any{})
+}
+
+// newLegacyConnWithCapabilities is newLegacyConn for a client declaring
+// capabilities.
+func newLegacyConnWithCapabilities(
+	ctx context.Context, t *testing.T, server *mcp.Server, capabilities map[string]any,
+) *legacyConn {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	conn, err := clientTransport.Connect(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	c := &legacyConn{t: t, conn: conn}
+	resp, _ := c.call(ctx, "initialize", map[string]any{
+		"protocolVersion": "2025-11-25",
+		"capabilities":    capabilities,
+		"clientInfo":      map[string]any{"name": "legacy", "version": "0.0.1"},
+	})
+	require.NoError(t, resp.Error)
+	require.NoError(t, conn.Write(ctx, &jsonrpc.Request{Method: "notifications/initialized", Params: json.RawMessage("{}")}))
+	return c
+}
+
+// call sends a request and returns its response, along with the
+// notifications, and any requests c.answer didn't answer, that arrived
+// before it.
+func (c *legacyConn) call(ctx context.Context, method string, params any) (*jsonrpc.Response, []*jsonrpc.Request) {
+	c.t.Helper()
+	c.nextID++
+	id, err := jsonrpc.MakeID(float64(c.nextID))
+	require.NoError(c.t, err)
+	raw, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.conn.Write(ctx, &jsonrpc.Request{ID: id, Method: method, Params: raw}))
+
+	var notifications []*jsonrpc.Request
+	for {
+		msg, err := c.conn.Read(ctx)
+		require.NoError(c.t, err)
+		switch m := msg.(type) {
+		case *jsonrpc.Request:
+			if m.ID.IsValid() && c.answer != nil {
+				if result := c.answer(m); result != nil {
+					raw, err := json.Marshal(result)
+					require.NoError(c.t, err)
+					require.NoError(c.t, c.conn.Write(ctx, &jsonrpc.Response{ID: m.ID, Result: raw}))
+				}
+				continue
+			}
+			notifications = append(notifications, m)
+		case *jsonrpc.Response:
+			if m.ID == id {
+				return m, notifications
+			}
+		}
+	}
+}
+
+func TestLogSequence_SetLevel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addLoggingTools(server)
+	c := newLegacyConn(ctx, t, server)
+
+	// Nothing is sent until a level is set.
+	resp, notifications := c.call(ctx, "tools/call", map[string]any{"name": "log_sequence", "arguments": map[string]any{}})
+	require.NoError(t, resp.Error)
+	assert.Empty(t, notifications)
+	var result mcp.CallToolResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.JSONEq(t, `{"emitted":8,"sent":0,"sent_levels":[]}`, mustJSON(t, result.StructuredContent))
+
+	resp, _ = c.call(ctx, methodSetLevel, map[string]any{"level": "loud"})
+	require.Error(t, resp.Error)
+	assert.Contains(t, resp.Error.Error(), "unknown log level")
+
+	resp, _ = c.call(ctx, methodSetLevel, map[string]any{"level": "error"})
+	require.NoError(t, resp.Error)
+
+	resp, notifications = c.call(ctx, "tools/call", map[string]any{
+		"name": "log_sequence", "arguments": map[string]any{"repeat": 2, "logger": "test", "structured": true},
+	})
+	require.NoError(t, resp.Error)
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.JSONEq(t, `{"emitted":16,"sent":8,"level":"error","sent_levels":["error","critical","alert","emergency"]}`,
+		mustJSON(t, result.StructuredContent))
+
+	require.Len(t, notifications, 8)
+	for i, n := range notifications {
+		assert.Equal(t, "notifications/message", n.Method)
+		var params mcp.LoggingMessageParams
+		require.NoError(t, json.Unmarshal(n.Params, &params))
+		assert.Equal(t, logLevels[4+i%4], params.Level)
+		assert.Equal(t, "test", params.Logger)
+		data := params.Data.(map[string]any)
+		assert.Equal(t, float64(5+i%4+8*(i/4)), data["sequence"])
+	}
+}
+
+// TestLogSequence_MetaLevel covers the 2026-07-28 protocol, where the
+// level comes with each request.
+func TestLogSequence_MetaLevel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var got []*mcp.LoggingMessageParams
+	session := connectInMemory(ctx, t, nil, addLoggingTools, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, req.Params)
+		},
+	})
+
+	params := &mcp.CallToolParams{Name: "log_sequence", Arguments: map[string]any{}}
+	params.Meta = mcp.Meta{mcp.MetaKeyLogLevel: "warning"}
+	result, err := session.CallTool(ctx, params)
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+	assert.Equal(t, 5.0, result.StructuredContent.(map[string]any)["sent"])
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 5
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, mcp.LoggingLevel("warning"), got[0].Level)
+	assert.Equal(t, "log message 4 at level warning", got[0].Data)
+	assert.Equal(t, defaultLogger, got[0].Logger)
+}
+
+// TestLogLevelState_ClosedSessions checks the level of a session that has
+// ended is dropped when another session sets one.
+func TestLogLevelState_ClosedSessions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	state := &logLevelState{server: server, levels: map[mcp.Session]mcp.LoggingLevel{}}
+	server.AddReceivingMiddleware(state.middleware)
+
+	first := newLegacyConn(ctx, t, server)
+	resp, _ := first.call(ctx, methodSetLevel, map[string]any{"level": "error"})
+	require.NoError(t, resp.Error)
+	require.NoError(t, first.conn.Close())
+	require.Eventually(t, func() bool { return len(connectedSessions(server)) == 0 }, time.Second, 10*time.Millisecond)
+
+	second := newLegacyConn(ctx, t, server)
+	resp, _ = second.call(ctx, methodSetLevel, map[string]any{"level": "info"})
+	require.NoError(t, resp.Error)
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	assert.Len(t, state.levels, 1)
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"net/http"
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
@@ -26,42 +30,102 @@ type EchoResponse struct {
 	Output string `json:"output"`
 }
 
-var alphanumericRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+// defaultEchoPattern is the echo tool's input pattern unless ECHO_PATTERN
+// replaces it.
+const defaultEchoPattern = `^[a-zA-Z0-9]+$`
+
+var echoPattern = defaultEchoPattern
+var echoRegex = regexp.MustCompile(defaultEchoPattern)
 var transport string
 var port int
 var stateless bool
+var attachStdio bool
+var ssePath = defaultSSEPath
+var mcpPath = defaultMCPPath
+var wsPath = defaultWSPath
+var fixedClock, _ = time.Parse(time.RFC3339, defaultFixedClock)
+var basePath string
+var bindAddress string
 var authHeader string
 var authValue string
+var authCredentials []credential
+var authTokens map[string][]string
+var toolScopeOverrides map[string][]string
+var resourceMetadataURL string
+var stdioAuthMetaKey string
+var stdioAuthEnv string
+var corsMode string
+var allowedOrigins []string
+var allowedHosts []string
 var backendMode string
 var barrierN int
 var hangAfterN int
 var crashAfterN int
 var barrierTimeout time.Duration
-
-func validateAlphanumeric(input string) bool {
-	return alphanumericRegex.MatchString(input)
+var resourceUpdateInterval time.Duration
+var syntheticItems int
+var pageSize int
+var cursorMode string
+
+// validateEchoInput reports whether input matches the echo tool's pattern:
+// alphanumeric only, unless ECHO_PATTERN replaces it.
+func validateEchoInput(input string) bool {
+	return echoRegex.MatchString(input)
 }
 
-func checkAuth(r *http.Request) error {
-	if authHeader == "" {
-		return nil
+// matchCredential returns the first accepted credential (see
+// acceptedCredentials) that r presents. It returns a nil credential and no
+// error when no credentials are configured, i.e. auth is disabled.
+func matchCredential(r *http.Request) (*credential, error) {
+	creds := acceptedCredentials()
+	if len(creds) == 0 {
+		return nil, nil
 	}
-	if r.Header.Get(authHeader) != authValue {
-		return errors.New("unauthorized")
+	for i := range creds {
+		if creds[i].matches(r) {
+			return &creds[i], nil
+		}
 	}
-	return nil
+	return nil, errors.New("unauthorized")
+}
+
+func checkAuth(r *http.Request) error {
+	_, err := matchCredential(r)
+	return err
 }
 
 func authWrapper(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if err := checkAuth(r); err != nil {
+		c, err := matchCredential(r)
+		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
+		if c != nil {
+			r = r.WithContext(withCredential(r.Context(), c))
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// nullArgumentsMiddleware replaces absent or null tools/call arguments
+// with an empty object. The SDK applies input schema defaults by writing
+// into the decoded arguments map, and a null decodes to a nil map, so
+// calling any tool whose schema declares defaults without arguments would
+// otherwise panic the server.
+func nullArgumentsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == methodToolsCall {
+			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok && params != nil {
+				if args := bytes.TrimSpace(params.Arguments); len(args) == 0 || bytes.Equal(args, []byte("null")) {
+					params.Arguments = json.RawMessage("{}")
+				}
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
 func echoHandler(_ context.Context, req *mcp.CallToolRequest, params EchoRequest) (*mcp.CallToolResult, EchoResponse, error) {
 	// Extract metadata from request to echo back in response
 	var metadata mcp.Meta
@@ -70,10 +134,14 @@ func echoHandler(_ context.Context, req *mcp.CallToolRequest, params EchoRequest
 		metadata = req.Params.Meta
 	}
 
-	if !validateAlphanumeric(params.Input) {
+	if !validateEchoInput(params.Input) {
+		text := "input must be alphanumeric only"
+		if echoPattern != defaultEchoPattern {
+			text = fmt.Sprintf("input must match ECHO_PATTERN %s", echoPattern)
+		}
 		// Echo back metadata even in error cases
 		result := &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: "input must be alphanumeric only"}},
+			Content: []mcp.Content{&mcp.TextContent{Text: text}},
 			IsError: true,
 		}
 		if len(metadata) > 0 {
@@ -142,91 +210,130 @@ func main() {
 	// Parse command line flags
 	parseConfig()
 
-	// Create MCP server
+	// Create MCP server. The live resource's subscribe handlers and the
+	// completion handler can only be set here.
+	live := newLiveResource()
+	opts := live.serverOptions()
+	opts.PageSize = pageSize
+	opts.CompletionHandler = completionHandler
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "echo-server",
 		Version: "1.0.0",
-	}, nil)
+	}, opts)
 
 	// Create custom schema for input validation
+	inputDescription, toolDescription := "Alphanumeric string to echo back", "an alphanumeric string"
+	if echoPattern != defaultEchoPattern {
+		inputDescription = "String matching " + echoPattern + " to echo back"
+		toolDescription = "a string matching " + echoPattern
+	}
 	inputSchema := &jsonschema.Schema{
 		Type: "object",
 		Properties: map[string]*jsonschema.Schema{
 			"input": {
 				Type:        "string",
-				Pattern:     "^[a-zA-Z0-9]+$",
-				Description: "Alphanumeric string to echo back",
+				Pattern:     echoPattern,
+				Description: inputDescription,
 			},
 		},
 		Required: []string{"input"},
 	}
 
 	// Add echo tool to server using the new API
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name: "echo",
-		Description: "Echo back an alphanumeric string for deterministic testing. " +
+		Description: "Echo back " + toolDescription + " for deterministic testing. " +
 			"Also echoes back any _meta field from the request for testing metadata propagation.",
 		InputSchema: inputSchema,
 	}, echoHandler)
 
+	addEchoRawTool(server)
+	addPayloadTool(server)
+
+	addTool(server, &mcp.Tool{
+		Name: "whoami",
+		Description: "Report which accepted credential authenticated this session, " +
+			"for verifying that a gateway forwards each tenant's credential intact.",
+	}, whoamiHandler)
+
+	addStructuredTools(server)
+	addDataTools(server)
+	addMediaTools(server)
+	addProgressTool(server)
+	addCancellationTools(server)
+	addLoggingTools(server)
+	addResources(server)
+	addPrompts(server)
+	addLiveResource(server, live)
+	addSyntheticItems(server, syntheticItems)
+	addCatalogTool(server)
+	addSamplingTool(server)
+	addElicitationTool(server)
+	addRootsTool(server)
+	addAnnotatedTools(server)
+
+	maps.Copy(toolScopes, toolScopeOverrides)
+
+	server.AddReceivingMiddleware(nullArgumentsMiddleware)
+	if cursorMode != cursorModeValid {
+		server.AddReceivingMiddleware(newCursorMiddleware(cursorMode))
+	}
+	if syntheticItems > 0 || pageSize > 0 || cursorMode != cursorModeValid {
+		log.Printf("Pagination config: SYNTHETIC_ITEMS=%d, PAGE_SIZE=%d, CURSOR_MODE=%s", syntheticItems, pageSize, cursorMode)
+	}
+
 	cs := &counterState{mode: backendMode, hangAfter: hangAfterN, crashAfter: crashAfterN}
 	br := &barrier{n: barrierN, timeout: barrierTimeout}
 	server.AddReceivingMiddleware(newFaultMiddleware(backendMode, cs, br))
 
 	log.Printf("Fault-injection config: BACKEND_MODE=%s", faultConfigDescription(backendMode))
 
+	if len(authTokens) > 0 {
+		server.AddReceivingMiddleware(websocketScopeMiddleware)
+	}
+
 	ctx := context.Background()
 
+	if resourceUpdateInterval > 0 {
+		log.Printf("Touching %s every %v", liveResourceURI, resourceUpdateInterval)
+		go live.run(ctx, resourceUpdateInterval)
+	}
+
+	sa := &stdioAuth{metaKey: stdioAuthMetaKey, envVar: stdioAuthEnv, sessions: map[mcp.Session]*credential{}}
+	if sa.enabled() && (transport == transportStdio || attachStdio) {
+		server.AddReceivingMiddleware(sa.middleware)
+		log.Printf("Stdio auth: requiring an accepted credential (STDIO_AUTH_META_KEY=%q, STDIO_AUTH_ENV=%q)",
+			stdioAuthMetaKey, stdioAuthEnv)
+	}
+
 	switch transport {
-	case "stdio":
+	case transportStdio:
 		log.Println("Starting MCP server with stdio transport")
-		stdioTransport := &mcp.StdioTransport{}
-		if err := server.Run(ctx, stdioTransport); err != nil {
+		if err := runStdio(ctx, server); err != nil {
 			log.Fatal("Failed to run server:", err)
 		}
 
-	case "sse":
-		log.Printf("Starting MCP server with SSE transport on port %d", port)
-		log.Printf("SSE endpoint: http://localhost:%d/sse", port)
-		log.Printf("Messages will be handled automatically by the SSE handler")
-
-		handler := mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server {
-			return server
-		}, nil)
-
-		// Mount the SSE handler at /sse - it will handle both GET (SSE stream) and POST (messages) requests
-		http.Handle("/sse", authWrapper(handler))
-
-		// Create server with timeouts to address G114 gosec issue
-		srv := &http.Server{
-			Addr:         ":" + strconv.Itoa(port),
-			ReadTimeout:  30 * time.Second,
-			WriteTimeout: 30 * time.Second,
-			IdleTimeout:  60 * time.Second,
+	case transportSSE, transportStreamableHTTP, transportWebSocket, transportBoth:
+		log.Printf("Starting MCP server with %s transport on port %d", transport, port)
+		mux := newMux(server, transport)
+
+		if attachStdio {
+			// A stdio session that ends (e.g. stdin closed because the
+			// container was started without -i) must not take the HTTP
+			// transports down with it.
+			log.Println("Also attaching stdio transport to the same server")
+			go func() {
+				err := runStdio(ctx, server)
+				log.Printf("Stdio session ended (%v); HTTP transports keep serving", err)
+			}()
 		}
-		log.Fatal(srv.ListenAndServe())
 
-	case "streamable-http":
-		log.Printf("Starting MCP server with streamable HTTP transport on port %d (stateless=%t)", port, stateless)
-
-		handler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
-			return server
-		}, &mcp.StreamableHTTPOptions{Stateless: stateless})
-
-		http.Handle("/mcp", authWrapper(handler))
-
-		// Create server with timeouts to address G114 gosec issue
-		srv := &http.Server{
-			Addr:         ":" + strconv.Itoa(port),
-			ReadTimeout:  30 * time.Second,
-			WriteTimeout: 30 * time.Second,
-			IdleTimeout:  60 * time.Second,
-		}
-		log.Fatal(srv.ListenAndServe())
+		log.Fatal(serveHTTP(mux))
 
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown transport type: %s\n", transport)
-		fmt.Fprintf(os.Stderr, "Supported transports: stdio, sse, streamable-http\n")
+		fmt.Fprintf(os.Stderr, "Supported transports: %s, %s, %s, %s, %s\n",
+			transportStdio, transportSSE, transportStreamableHTTP, transportWebSocket, transportBoth)
 		os.Exit(1)
 	}
 }
@@ -234,9 +341,11 @@ func main() {
 // parseConfig parses the command line flags and environment variables
 // to set the transport and port for the MCP server
 func parseConfig() {
-	flag.StringVar(&transport, "transport", "stdio", "Transport type: stdio, sse, or streamable-http")
+	flag.StringVar(&transport, "transport", transportStdio,
+		"Transport type: stdio, sse, streamable-http, websocket, or both (all HTTP-based transports)")
 	flag.IntVar(&port, "port", 8080, "Port number for HTTP-based transports")
 	flag.BoolVar(&stateless, "stateless", false, "Run the streamable-http transport in stateless mode (ignored by stdio and sse)")
+	flag.BoolVar(&attachStdio, "attach-stdio", false, "Also serve stdio alongside the HTTP-based transports (ignored by stdio)")
 	flag.Parse()
 
 	// Use environment variables if provided, otherwise use flag values
@@ -256,9 +365,109 @@ func parseConfig() {
 		}
 		stateless = boolValue
 	}
+	if a, ok := os.LookupEnv("ATTACH_STDIO"); ok {
+		boolValue, err := strconv.ParseBool(a)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ATTACH_STDIO must be a boolean (e.g. true/false; got %q)\n", a)
+			os.Exit(1)
+		}
+		attachStdio = boolValue
+	}
+
+	ssePath, mcpPath, wsPath = defaultSSEPath, defaultMCPPath, defaultWSPath
+	if p, ok := os.LookupEnv("SSE_PATH"); ok {
+		ssePath = p
+	}
+	if p, ok := os.LookupEnv("MCP_PATH"); ok {
+		mcpPath = p
+	}
+	if p, ok := os.LookupEnv("WS_PATH"); ok {
+		wsPath = p
+	}
+	basePath = strings.TrimRight(os.Getenv("BASE_PATH"), "/")
+	if err := validatePathConfig(ssePath, mcpPath, wsPath, basePath); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid endpoint path configuration: %s\n", err)
+		os.Exit(1)
+	}
+	bindAddress = os.Getenv("BIND_ADDRESS")
+	if path, ok := unixSocketPath(); ok && path == "" {
+		fmt.Fprintf(os.Stderr, "BIND_ADDRESS must name a socket path after %s (e.g. unix:///tmp/yardstick.sock)\n", unixSocketPrefix)
+		os.Exit(1)
+	}
+
+	if p, ok := os.LookupEnv("ECHO_PATTERN"); ok && p != "" {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ECHO_PATTERN is not a valid regular expression: %s\n", err)
+			os.Exit(1)
+		}
+		echoPattern, echoRegex = p, re
+	}
+	if c, ok := os.LookupEnv("FIXED_CLOCK"); ok {
+		t, err := time.Parse(time.RFC3339, c)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "FIXED_CLOCK must be an RFC 3339 timestamp (e.g. %s; got %q)\n", defaultFixedClock, c)
+			os.Exit(1)
+		}
+		fixedClock = t
+	}
 
 	authHeader = os.Getenv("AUTH_HEADER")
 	authValue = os.Getenv("AUTH_VALUE")
+	resourceMetadataURL = os.Getenv("AUTH_RESOURCE_METADATA_URL")
+
+	var err error
+	if f := os.Getenv("AUTH_FILE"); f != "" {
+		if authCredentials, err = loadCredentials(f); err != nil {
+			fmt.Fprintf(os.Stderr, "AUTH_FILE is invalid: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if authTokens, err = parseScopeMap(os.Getenv("AUTH_TOKENS")); err != nil {
+		fmt.Fprintf(os.Stderr, "AUTH_TOKENS is malformed: %s\n", err)
+		os.Exit(1)
+	}
+	if toolScopeOverrides, err = parseScopeMap(os.Getenv("TOOL_SCOPES")); err != nil {
+		fmt.Fprintf(os.Stderr, "TOOL_SCOPES is malformed: %s\n", err)
+		os.Exit(1)
+	}
+
+	stdioAuthMetaKey = os.Getenv("STDIO_AUTH_META_KEY")
+	stdioAuthEnv = os.Getenv("STDIO_AUTH_ENV")
+	if (stdioAuthMetaKey != "" || stdioAuthEnv != "") && len(acceptedCredentials()) == 0 {
+		fmt.Fprintf(os.Stderr, "STDIO_AUTH_META_KEY/STDIO_AUTH_ENV require AUTH_HEADER/AUTH_VALUE or AUTH_FILE "+
+			"to define accepted credentials\n")
+		os.Exit(1)
+	}
+
+	corsMode = os.Getenv("CORS_MODE")
+	if corsMode == "" {
+		corsMode = corsModeOff
+	}
+	allowedOrigins = splitList(os.Getenv("ALLOWED_ORIGINS"))
+	allowedHosts = splitList(os.Getenv("ALLOWED_HOSTS"))
+	if err := validateOriginConfig(corsMode, allowedOrigins, allowedHosts); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+
+	interval := envIntOr("RESOURCE_UPDATE_INTERVAL_MS", 0)
+	if interval < 0 {
+		fmt.Fprintf(os.Stderr, "RESOURCE_UPDATE_INTERVAL_MS must be >= 0 (got %d)\n", interval)
+		os.Exit(1)
+	}
+	resourceUpdateInterval = time.Duration(interval) * time.Millisecond
+
+	syntheticItems = envIntOr("SYNTHETIC_ITEMS", 0)
+	pageSize = envIntOr("PAGE_SIZE", 0)
+	cursorMode = os.Getenv("CURSOR_MODE")
+	if cursorMode == "" {
+		cursorMode = cursorModeValid
+	}
+	if err := validatePaginationConfig(syntheticItems, pageSize, cursorMode); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
 
 	backendMode = os.Getenv("BACKEND_MODE")
 	if backendMode == "" {
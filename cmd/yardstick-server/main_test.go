@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"net/http"
 	"net/http/httptest"
@@ -16,7 +17,38 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestValidateAlphanumeric(t *testing.T) {
+// connectInMemory connects an in-memory client, with clientOpts, to a new
+// server with opts that setup has added the tools and middleware under
+// test to. Both sessions are closed when the test ends.
+func connectInMemory(
+	ctx context.Context, t *testing.T, opts *mcp.ServerOptions, setup func(*mcp.Server), clientOpts *mcp.ClientOptions,
+) *mcp.ClientSession {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, opts)
+	setup(server)
+	session, err := connectClient(ctx, t, server, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, clientOpts))
+	require.NoError(t, err)
+	return session
+}
+
+// connectClient connects client to server over in-memory transports, for
+// tests that need to set either up beyond connectInMemory's options. The
+// sessions are closed when the test ends.
+func connectClient(ctx context.Context, t *testing.T, server *mcp.Server, client *mcp.Client) (*mcp.ClientSession, error) {
+	t.Helper()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = serverSession.Close() })
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session, nil
+}
+
+func TestValidateEchoInput(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
@@ -36,7 +68,7 @@ func TestValidateAlphanumeric(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := validateAlphanumeric(tt.input)
+			result := validateEchoInput(tt.input)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -579,6 +611,58 @@ func TestParseConfig_StatelessFlagAndEnv(t *testing.T) {
 	}
 }
 
+func TestParseConfig_AttachStdioEnv(t *testing.T) {
+	origAttach, origTransport := attachStdio, transport
+	origArgs := os.Args
+	defer func() {
+		attachStdio, transport = origAttach, origTransport
+		os.Args = origArgs
+	}()
+
+	withFreshFlagSet(t)
+	os.Args = []string{"yardstick-server", "-transport", transportBoth}
+	t.Setenv("ATTACH_STDIO", "true")
+
+	parseConfig()
+
+	assert.True(t, attachStdio)
+	assert.Equal(t, transportBoth, transport)
+}
+
+func TestParseConfig_FixedClock(t *testing.T) {
+	origClock := fixedClock
+	origArgs := os.Args
+	defer func() {
+		fixedClock = origClock
+		os.Args = origArgs
+	}()
+
+	withFreshFlagSet(t)
+	os.Args = []string{"yardstick-server"}
+	t.Setenv("FIXED_CLOCK", "2030-06-15T08:00:00+02:00")
+
+	parseConfig()
+
+	assert.Equal(t, time.Date(2030, 6, 15, 6, 0, 0, 0, time.UTC), fixedClock.UTC())
+}
+
+func TestParseConfig_ResourceUpdateInterval(t *testing.T) {
+	origInterval := resourceUpdateInterval
+	origArgs := os.Args
+	defer func() {
+		resourceUpdateInterval = origInterval
+		os.Args = origArgs
+	}()
+
+	withFreshFlagSet(t)
+	os.Args = []string{"yardstick-server"}
+	t.Setenv("RESOURCE_UPDATE_INTERVAL_MS", "250")
+
+	parseConfig()
+
+	assert.Equal(t, 250*time.Millisecond, resourceUpdateInterval)
+}
+
 func TestFaultConfigDescription(t *testing.T) {
 	origMode, origBarrierN, origHangAfter, origCrashAfter, origTimeout :=
 		backendMode, barrierN, hangAfterN, crashAfterN, barrierTimeout
@@ -628,3 +712,23 @@ func TestStreamableHTTPStatelessMode(t *testing.T) {
 		})
 	}
 }
+
+func TestNullArgumentsMiddleware(t *testing.T) {
+	var got json.RawMessage
+	handler := nullArgumentsMiddleware(func(_ context.Context, _ string, req mcp.Request) (mcp.Result, error) {
+		got = req.GetParams().(*mcp.CallToolParamsRaw).Arguments
+		return nil, nil
+	})
+
+	for _, args := range []string{"", "null", " null "} {
+		req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "echo", Arguments: json.RawMessage(args)}}
+		_, err := handler(context.Background(), methodToolsCall, req)
+		require.NoError(t, err)
+		assert.JSONEq(t, "{}", string(got), "arguments %q", args)
+	}
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "echo", Arguments: json.RawMessage(`{"input":"a"}`)}}
+	_, err := handler(context.Background(), methodToolsCall, req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"input":"a"}`, string(got))
+}
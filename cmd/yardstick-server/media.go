@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultImageSide = 64
+	maxImageSide     = 1024
+
+	defaultToneMillis = 500
+	maxToneMillis     = 10000
+	defaultToneHz     = 440
+	minToneHz         = 20
+	maxToneHz         = 3000
+	// toneSampleRate is low because the tone is only there to be carried,
+	// not listened to closely; 8 kHz mono keeps a second of it at 16 KB.
+	toneSampleRate = 8000
+
+	resourceText = "text"
+	resourceBlob = "blob"
+
+	mediaImageURI = "yardstick://media/image.png"
+	mediaAudioURI = "yardstick://media/tone.wav"
+	mediaTextURI  = "yardstick://media/readme.txt"
+
+	mimePNG  = "image/png"
+	mimeWAV  = "audio/wav"
+	mimeText = "text/plain"
+
+	mediaText = "Yardstick deterministic embedded resource.\n" +
+		"This text is the same on every call, so a client can compare it byte for byte.\n"
+)
+
+// ImageRequest represents the request for the image tool
+type ImageRequest struct {
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// AudioRequest represents the request for the audio tool
+type AudioRequest struct {
+	DurationMS  int `json:"duration_ms,omitempty"`
+	FrequencyHz int `json:"frequency_hz,omitempty"`
+}
+
+// EmbeddedResourceRequest represents the request for the embedded_resource
+// tool
+type EmbeddedResourceRequest struct {
+	Kind string `json:"kind,omitempty"`
+}
+
+// generatePNG draws a width×height gradient, red increasing to the right
+// and green downwards, so a decoded image can be spot-checked pixel by
+// pixel as well as byte for byte.
+func generatePNG(width, height int) ([]byte, error) {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.SetNRGBA(x, y, color.NRGBA{R: gradient(x, width), G: gradient(y, height), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gradient(i, n int) uint8 {
+	if n == 1 {
+		return 0
+	}
+	return uint8(i * 255 / (n - 1)) // #nosec G115 - i < n, so the result is at most 255
+}
+
+// generateWAV returns a 16-bit mono PCM WAV file holding a sine tone at
+// half amplitude.
+func generateWAV(durationMS, frequencyHz int) []byte {
+	samples := toneSampleRate * durationMS / 1000
+	dataSize := uint32(samples * 2) // #nosec G115 - bounded by maxToneMillis
+
+	var buf bytes.Buffer
+	buf.Grow(44 + samples*2)
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, struct {
+		ChunkSize     uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+	}{16, 1, 1, toneSampleRate, toneSampleRate * 2, 2, 16})
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, dataSize)
+	for i := range samples {
+		v := 0.5 * math.Sin(2*math.Pi*float64(frequencyHz)*float64(i)/toneSampleRate)
+		_ = binary.Write(&buf, binary.LittleEndian, int16(math.Round(v*math.MaxInt16)))
+	}
+	return buf.Bytes()
+}
+
+func imageContent(width, height int) (*mcp.ImageContent, error) {
+	if width == 0 {
+		width = defaultImageSide
+	}
+	if height == 0 {
+		height = defaultImageSide
+	}
+	if width < 1 || width > maxImageSide || height < 1 || height > maxImageSide {
+		return nil, fmt.Errorf("width and height must be between 1 and %d (got %dx%d)", maxImageSide, width, height)
+	}
+	data, err := generatePNG(width, height)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ImageContent{Data: data, MIMEType: mimePNG}, nil
+}
+
+func audioContent(durationMS, frequencyHz int) (*mcp.AudioContent, error) {
+	if durationMS == 0 {
+		durationMS = defaultToneMillis
+	}
+	if frequencyHz == 0 {
+		frequencyHz = defaultToneHz
+	}
+	if durationMS < 1 || durationMS > maxToneMillis {
+		return nil, fmt.Errorf("duration_ms must be between 1 and %d (got %d)", maxToneMillis, durationMS)
+	}
+	if frequencyHz < minToneHz || frequencyHz > maxToneHz {
+		return nil, fmt.Errorf("frequency_hz must be between %d and %d (got %d)", minToneHz, maxToneHz, frequencyHz)
+	}
+	return &mcp.AudioContent{Data: generateWAV(durationMS, frequencyHz), MIMEType: mimeWAV}, nil
+}
+
+// embeddedResource returns the fixed text resource, or the default image
+// as a blob resource.
+func embeddedResource(kind string) (*mcp.EmbeddedResource, error) {
+	switch kind {
+	case "", resourceText:
+		return &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+			URI: mediaTextURI, MIMEType: mimeText, Text: mediaText,
+		}}, nil
+	case resourceBlob:
+		img, err := imageContent(0, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+			URI: mediaImageURI, MIMEType: mimePNG, Blob: img.Data,
+		}}, nil
+	}
+	return nil, fmt.Errorf("kind must be %s or %s (got %q)", resourceText, resourceBlob, kind)
+}
+
+// resourceLink links to the default image rather than carrying it, with
+// the size it would have.
+func resourceLink() (*mcp.ResourceLink, error) {
+	img, err := imageContent(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	size := int64(len(img.Data))
+	return &mcp.ResourceLink{
+		URI:         mediaImageURI,
+		Name:        "image.png",
+		Title:       "Gradient image",
+		Description: fmt.Sprintf("The image tool's default %dx%d gradient", defaultImageSide, defaultImageSide),
+		MIMEType:    mimePNG,
+		Size:        &size,
+	}, nil
+}
+
+// contentResult wraps content that was built without error in a result.
+// The media tools have no structured output, so they return any as their
+// output type and the SDK sends the content as is.
+func contentResult(content mcp.Content, err error) (*mcp.CallToolResult, any, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{content}}, nil, nil
+}
+
+func imageHandler(_ context.Context, _ *mcp.CallToolRequest, params ImageRequest) (*mcp.CallToolResult, any, error) {
+	return contentResult(imageContent(params.Width, params.Height))
+}
+
+func audioHandler(_ context.Context, _ *mcp.CallToolRequest, params AudioRequest) (*mcp.CallToolResult, any, error) {
+	return contentResult(audioContent(params.DurationMS, params.FrequencyHz))
+}
+
+func embeddedResourceHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params EmbeddedResourceRequest,
+) (*mcp.CallToolResult, any, error) {
+	return contentResult(embeddedResource(params.Kind))
+}
+
+func resourceLinkHandler(_ context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+	return contentResult(resourceLink())
+}
+
+// mixedContentHandler returns one item of every content type, in a fixed
+// order, with each media item at its defaults.
+func mixedContentHandler(_ context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+	img, err := imageContent(0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	audio, err := audioContent(0, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	embedded, err := embeddedResource(resourceText)
+	if err != nil {
+		return nil, nil, err
+	}
+	link, err := resourceLink()
+	if err != nil {
+		return nil, nil, err
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{
+		&mcp.TextContent{Text: "One content item of each type follows: image, audio, resource, resource_link."},
+		img, audio, embedded, link,
+	}}, nil, nil
+}
+
+// addMediaTools registers the tools that return non-text content. All of
+// it is generated deterministically, so the same arguments always produce
+// the same bytes.
+func addMediaTools(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name:        "image",
+		Description: "Return a generated PNG gradient as image content.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"width":  boundedProp("integer", "Width in pixels", 1, maxImageSide, defaultImageSide),
+			"height": boundedProp("integer", "Height in pixels", 1, maxImageSide, defaultImageSide),
+		}),
+	}, imageHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "audio",
+		Description: fmt.Sprintf("Return a generated sine tone as audio content (16-bit mono WAV, %d Hz).", toneSampleRate),
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"duration_ms":  boundedProp("integer", "Duration in milliseconds", 1, maxToneMillis, defaultToneMillis),
+			"frequency_hz": boundedProp("integer", "Tone frequency in hertz", minToneHz, maxToneHz, defaultToneHz),
+		}),
+	}, audioHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "embedded_resource",
+		Description: "Return an embedded resource: a fixed text document, or the default image as a blob.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"kind": enumProp("Whether the resource carries text or a base64 blob", resourceText, resourceText, resourceBlob),
+		}),
+	}, embeddedResourceHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "resource_link",
+		Description: "Return a link to the default image resource instead of its contents.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{}),
+	}, resourceLinkHandler)
+
+	addTool(server, &mcp.Tool{
+		Name:        "mixed_content",
+		Description: "Return text, image, audio, embedded resource and resource link content in one result.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{}),
+	}, mixedContentHandler)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratePNG(t *testing.T) {
+	data, err := generatePNG(3, 2)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 3, img.Bounds().Dx())
+	assert.Equal(t, 2, img.Bounds().Dy())
+	r, g, b, _ := img.At(2, 1).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0x8080}, []uint32{r, g, b})
+	r, g, _, _ = img.At(0, 0).RGBA()
+	assert.Equal(t, []uint32{0, 0}, []uint32{r, g})
+
+	again, err := generatePNG(3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestGenerateWAV(t *testing.T) {
+	data := generateWAV(250, 1000)
+
+	samples := toneSampleRate / 4
+	require.Len(t, data, 44+samples*2)
+	assert.Equal(t, "RIFF", string(data[0:4]))
+	assert.Equal(t, uint32(len(data)-8), binary.LittleEndian.Uint32(data[4:8]))
+	assert.Equal(t, "WAVEfmt ", string(data[8:16]))
+	assert.Equal(t, uint32(toneSampleRate), binary.LittleEndian.Uint32(data[24:28]))
+	assert.Equal(t, "data", string(data[36:40]))
+	assert.Equal(t, uint32(samples*2), binary.LittleEndian.Uint32(data[40:44]))
+
+	// A 1 kHz tone at 8 kHz peaks at the third sample, at half amplitude.
+	assert.Equal(t, int16(0), int16(binary.LittleEndian.Uint16(data[44:46])))
+	assert.Equal(t, int16(16384), int16(binary.LittleEndian.Uint16(data[48:50])))
+}
+
+func TestMediaContent_Bounds(t *testing.T) {
+	_, err := imageContent(0, maxImageSide+1)
+	assert.ErrorContains(t, err, "width and height")
+	_, err = imageContent(-1, 1)
+	assert.ErrorContains(t, err, "width and height")
+	_, err = audioContent(maxToneMillis+1, 0)
+	assert.ErrorContains(t, err, "duration_ms")
+	_, err = audioContent(0, minToneHz-1)
+	assert.ErrorContains(t, err, "frequency_hz")
+	_, err = embeddedResource("video")
+	assert.ErrorContains(t, err, "kind")
+
+	img, err := imageContent(0, 0)
+	require.NoError(t, err)
+	decoded, err := png.DecodeConfig(bytes.NewReader(img.Data))
+	require.NoError(t, err)
+	assert.Equal(t, defaultImageSide, decoded.Width)
+}
+
+// TestMediaTools_RoundTrip checks every content type survives the SDK's
+// wire encoding and decoding intact.
+func TestMediaTools_RoundTrip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session := connectInMemory(ctx, t, nil, func(server *mcp.Server) {
+		addMediaTools(server)
+		// Calls below omit arguments, as a client would for tools whose
+		// arguments are all optional.
+		server.AddReceivingMiddleware(nullArgumentsMiddleware)
+	}, nil)
+
+	wantImage, err := generatePNG(defaultImageSide, defaultImageSide)
+	require.NoError(t, err)
+	wantAudio := generateWAV(defaultToneMillis, defaultToneHz)
+
+	call := func(name string, args map[string]any) []mcp.Content {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "%s: %v", name, result.Content)
+		assert.Nil(t, result.StructuredContent, name)
+		return result.Content
+	}
+
+	content := call("image", map[string]any{"width": 10, "height": 20})
+	require.Len(t, content, 1)
+	img := content[0].(*mcp.ImageContent)
+	assert.Equal(t, mimePNG, img.MIMEType)
+	want, err := generatePNG(10, 20)
+	require.NoError(t, err)
+	assert.Equal(t, want, img.Data)
+
+	content = call("audio", nil)
+	require.Len(t, content, 1)
+	assert.Equal(t, wantAudio, content[0].(*mcp.AudioContent).Data)
+
+	content = call("embedded_resource", map[string]any{"kind": resourceBlob})
+	require.Len(t, content, 1)
+	resource := content[0].(*mcp.EmbeddedResource).Resource
+	assert.Equal(t, mediaImageURI, resource.URI)
+	assert.Equal(t, wantImage, resource.Blob)
+
+	content = call("resource_link", nil)
+	require.Len(t, content, 1)
+	link := content[0].(*mcp.ResourceLink)
+	assert.Equal(t, mediaImageURI, link.URI)
+	require.NotNil(t, link.Size)
+	assert.Equal(t, int64(len(wantImage)), *link.Size)
+
+	content = call("mixed_content", nil)
+	require.Len(t, content, 5)
+	assert.IsType(t, &mcp.TextContent{}, content[0])
+	assert.Equal(t, wantImage, content[1].(*mcp.ImageContent).Data)
+	assert.Equal(t, wantAudio, content[2].(*mcp.AudioContent).Data)
+	assert.Equal(t, mediaText, content[3].(*mcp.EmbeddedResource).Resource.Text)
+	assert.Equal(t, mediaImageURI, content[4].(*mcp.ResourceLink).URI)
+}
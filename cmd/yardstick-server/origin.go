@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+const (
+	// corsModeOff applies no Origin/Host validation and sends no CORS
+	// headers, leaving only the SDK's built-in localhost DNS-rebinding
+	// protection. It is the default.
+	corsModeOff = "off"
+	// corsModePermissive accepts any Origin and Host, disables the SDK's
+	// localhost protection, and answers CORS for every origin.
+	corsModePermissive = "permissive"
+	// corsModeStrict rejects requests whose Origin isn't in ALLOWED_ORIGINS
+	// or whose Host isn't in ALLOWED_HOSTS, and answers CORS only for
+	// allowed origins.
+	corsModeStrict = "strict"
+)
+
+// defaultAllowedHosts is the ALLOWED_HOSTS used in strict mode when none is
+// configured: a server that only answers to loopback names can't be reached
+// through a rebound DNS name.
+var defaultAllowedHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+const (
+	corsAllowedMethods = "GET, POST, DELETE, OPTIONS"
+	corsAllowedHeaders = "Accept, Authorization, Content-Type, Last-Event-ID, Mcp-Protocol-Version, Mcp-Session-Id"
+	corsExposedHeaders = "Mcp-Protocol-Version, Mcp-Session-Id, WWW-Authenticate"
+)
+
+// validateOriginConfig checks that mode is a known CORS_MODE value and that
+// ALLOWED_ORIGINS/ALLOWED_HOSTS are only set where they take effect, so a
+// forgotten CORS_MODE=strict doesn't silently leave the allow-lists unused.
+func validateOriginConfig(mode string, origins, hosts []string) error {
+	switch mode {
+	case corsModeStrict:
+		if slices.Contains(origins, "*") {
+			return fmt.Errorf("ALLOWED_ORIGINS must list explicit origins in strict mode (got \"*\"); use CORS_MODE=%s instead",
+				corsModePermissive)
+		}
+	case corsModeOff, corsModePermissive:
+		if len(origins) > 0 || len(hosts) > 0 {
+			return fmt.Errorf("ALLOWED_ORIGINS and ALLOWED_HOSTS require CORS_MODE=%s (got %q)", corsModeStrict, mode)
+		}
+	default:
+		return fmt.Errorf("unknown CORS_MODE %q: valid values are %s, %s, %s",
+			mode, corsModeOff, corsModePermissive, corsModeStrict)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated env var value, dropping empty entries
+// and surrounding whitespace.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// hostAllowed reports whether the request's Host matches an entry of
+// allowed, either exactly (host:port) or by hostname alone.
+func hostAllowed(host string, allowed []string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	hostname = strings.Trim(hostname, "[]")
+	for _, entry := range allowed {
+		if strings.EqualFold(entry, host) || strings.EqualFold(strings.Trim(entry, "[]"), hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// originWrapper applies CORS_MODE to an HTTP transport handler. It must be
+// the outermost wrapper: browsers send CORS preflights without credentials,
+// so they have to be answered before authWrapper would reject them.
+//
+// In strict mode a request with a disallowed Host, or with an Origin header
+// that isn't in ALLOWED_ORIGINS, gets a 403, as the MCP spec requires of
+// streamable HTTP servers to prevent DNS rebinding. Requests without an
+// Origin header (i.e. not from a browser) are not subject to the Origin
+// check.
+func originWrapper(next http.Handler) http.Handler {
+	if corsMode == corsModeOff {
+		return next
+	}
+	hosts := allowedHosts
+	if len(hosts) == 0 {
+		hosts = defaultAllowedHosts
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if corsMode == corsModeStrict {
+			if !hostAllowed(r.Host, hosts) {
+				http.Error(w, fmt.Sprintf("Forbidden: invalid Host header %q", r.Host), http.StatusForbidden)
+				return
+			}
+			if origin != "" && !slices.Contains(allowedOrigins, origin) {
+				http.Error(w, fmt.Sprintf("Forbidden: invalid Origin header %q", origin), http.StatusForbidden)
+				return
+			}
+		}
+
+		if origin != "" {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Add("Vary", "Origin")
+			h.Set("Access-Control-Allow-Credentials", "true")
+			h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			allowHeaders := corsAllowedHeaders
+			if requested := r.Header.Get("Access-Control-Request-Headers"); corsMode == corsModePermissive && requested != "" {
+				allowHeaders = requested
+			}
+			h.Set("Access-Control-Allow-Headers", allowHeaders)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateOriginConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		origins []string
+		hosts   []string
+		wantErr bool
+	}{
+		{name: "off", mode: corsModeOff},
+		{name: "permissive", mode: corsModePermissive},
+		{name: "strict with defaults", mode: corsModeStrict},
+		{name: "strict with lists", mode: corsModeStrict, origins: []string{"http://localhost:3000"}, hosts: []string{"mcp.example.com"}},
+		{name: "strict wildcard origin rejected", mode: corsModeStrict, origins: []string{"*"}, wantErr: true},
+		{name: "lists without strict rejected", mode: corsModeOff, hosts: []string{"mcp.example.com"}, wantErr: true},
+		{name: "lists with permissive rejected", mode: corsModePermissive, origins: []string{"http://a"}, wantErr: true},
+		{name: "unknown mode rejected", mode: "lenient", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOriginConfig(tt.mode, tt.origins, tt.hosts)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	assert.Nil(t, splitList(""))
+	assert.Equal(t, []string{"a", "b"}, splitList(" a, ,b ,"))
+}
+
+func TestHostAllowed(t *testing.T) {
+	allowed := []string{"localhost", "::1", "mcp.example.com:8443"}
+
+	assert.True(t, hostAllowed("localhost", allowed))
+	assert.True(t, hostAllowed("localhost:8080", allowed))
+	assert.True(t, hostAllowed("LOCALHOST:8080", allowed))
+	assert.True(t, hostAllowed("[::1]:8080", allowed))
+	assert.True(t, hostAllowed("mcp.example.com:8443", allowed))
+	assert.False(t, hostAllowed("mcp.example.com:80", allowed))
+	assert.False(t, hostAllowed("evil.example.com", allowed))
+}
+
+// withOriginConfig installs the given CORS_MODE and allow-lists for the
+// duration of the test.
+func withOriginConfig(t *testing.T, mode string, origins, hosts []string) {
+	t.Helper()
+	origMode, origOrigins, origHosts := corsMode, allowedOrigins, allowedHosts
+	t.Cleanup(func() { corsMode, allowedOrigins, allowedHosts = origMode, origOrigins, origHosts })
+	corsMode, allowedOrigins, allowedHosts = mode, origins, hosts
+}
+
+func TestOriginWrapper(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name        string
+		mode        string
+		origins     []string
+		hosts       []string
+		method      string
+		host        string
+		headers     map[string]string
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name: "off passes everything through", mode: corsModeOff,
+			host: "evil.example.com", headers: map[string]string{"Origin": "http://evil.example.com"},
+			wantStatus: http.StatusOK, wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name: "permissive echoes any origin", mode: corsModePermissive,
+			host: "evil.example.com", headers: map[string]string{"Origin": "http://evil.example.com"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":   "http://evil.example.com",
+				"Access-Control-Expose-Headers": corsExposedHeaders,
+			},
+		},
+		{
+			name: "permissive preflight allows requested headers", mode: corsModePermissive, method: http.MethodOptions,
+			host: "localhost", headers: map[string]string{
+				"Origin":                         "http://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "x-custom, mcp-session-id",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Headers": "x-custom, mcp-session-id",
+				"Access-Control-Allow-Methods": corsAllowedMethods,
+			},
+		},
+		{
+			name: "strict rejects rebound host", mode: corsModeStrict,
+			host: "attacker.example.com", wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "strict allows loopback host without origin", mode: corsModeStrict,
+			host: "localhost:8080", wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name: "strict rejects unlisted origin", mode: corsModeStrict, origins: []string{"http://app.example.com"},
+			host: "localhost:8080", headers: map[string]string{"Origin": "http://evil.example.com"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "strict allows listed origin and host", mode: corsModeStrict,
+			origins: []string{"http://app.example.com"}, hosts: []string{"mcp.example.com"},
+			host: "mcp.example.com", headers: map[string]string{"Origin": "http://app.example.com"},
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "http://app.example.com"},
+		},
+		{
+			name: "strict preflight uses fixed header list", mode: corsModeStrict, method: http.MethodOptions,
+			origins: []string{"http://app.example.com"},
+			host:    "localhost", headers: map[string]string{
+				"Origin":                         "http://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "x-custom",
+			},
+			wantStatus:  http.StatusNoContent,
+			wantHeaders: map[string]string{"Access-Control-Allow-Headers": corsAllowedHeaders},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withOriginConfig(t, tt.mode, tt.origins, tt.hosts)
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/mcp", nil)
+			req.Host = tt.host
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			originWrapper(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			for k, v := range tt.wantHeaders {
+				assert.Equal(t, v, rec.Header().Get(k), k)
+			}
+		})
+	}
+}
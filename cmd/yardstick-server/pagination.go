@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	methodListTools             = "tools/list"
+	methodListResources         = "resources/list"
+	methodListResourceTemplates = "resources/templates/list"
+	methodListPrompts           = "prompts/list"
+
+	// maxSyntheticItems bounds SYNTHETIC_ITEMS. Names are zero-padded to
+	// its width, so they list in numeric order.
+	maxSyntheticItems = 10000
+
+	cursorModeValid   = "valid"
+	cursorModeInvalid = "invalid"
+	cursorModeExpired = "expired"
+
+	// invalidCursor replaces every next cursor in invalid mode. It is not
+	// base64, so the SDK rejects it when it comes back.
+	invalidCursor = "yardstick-invalid-cursor!"
+)
+
+// validatePaginationConfig checks SYNTHETIC_ITEMS, PAGE_SIZE and
+// CURSOR_MODE. A PAGE_SIZE of 0 leaves the SDK's default in place.
+func validatePaginationConfig(items, pageSize int, mode string) error {
+	if items < 0 || items > maxSyntheticItems {
+		return fmt.Errorf("SYNTHETIC_ITEMS must be between 0 and %d (got %d)", maxSyntheticItems, items)
+	}
+	if pageSize < 0 {
+		return fmt.Errorf("PAGE_SIZE must be >= 1, or 0 for the default of %d (got %d)", mcp.DefaultPageSize, pageSize)
+	}
+	switch mode {
+	case cursorModeValid, cursorModeInvalid, cursorModeExpired:
+		return nil
+	}
+	return fmt.Errorf("unknown CURSOR_MODE %q: valid values are %s, %s, %s",
+		mode, cursorModeValid, cursorModeInvalid, cursorModeExpired)
+}
+
+// addSyntheticItems registers n tools, resources and prompts that exist
+// only to fill list pages. Each one says which it is when used, so an
+// aggregating gateway that routes a call to the wrong backend is caught.
+func addSyntheticItems(server *mcp.Server, n int) {
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("synthetic_%05d", i)
+		text := fmt.Sprintf("synthetic item %d", i)
+
+		addTool(server, &mcp.Tool{
+			Name:        name,
+			Description: fmt.Sprintf("Synthetic tool %d of %d, for testing tools/list pagination", i, n),
+			InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{}),
+		}, func(_ context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		})
+
+		server.AddResource(&mcp.Resource{
+			URI:         "yardstick://synthetic/" + name,
+			Name:        name,
+			MIMEType:    mimeText,
+			Description: fmt.Sprintf("Synthetic resource %d of %d, for testing resources/list pagination", i, n),
+			Size:        int64(len(text)),
+		}, resourceHandler(textContents(text)))
+
+		server.AddPrompt(&mcp.Prompt{
+			Name:        name,
+			Description: fmt.Sprintf("Synthetic prompt %d of %d, for testing prompts/list pagination", i, n),
+		}, func(_ context.Context, _ *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: text}},
+			}}, nil
+		})
+	}
+}
+
+func isListMethod(method string) bool {
+	return method == methodListTools || method == methodListResources ||
+		method == methodListResourceTemplates || method == methodListPrompts
+}
+
+// listCursor returns the cursor a list request presents, if any.
+func listCursor(req mcp.Request) string {
+	switch p := req.GetParams().(type) {
+	case *mcp.ListToolsParams:
+		if p != nil {
+			return p.Cursor
+		}
+	case *mcp.ListResourcesParams:
+		if p != nil {
+			return p.Cursor
+		}
+	case *mcp.ListResourceTemplatesParams:
+		if p != nil {
+			return p.Cursor
+		}
+	case *mcp.ListPromptsParams:
+		if p != nil {
+			return p.Cursor
+		}
+	}
+	return ""
+}
+
+// nextCursor returns a pointer to the next cursor of a list result, or nil
+// if result isn't one.
+func nextCursor(result mcp.Result) *string {
+	switch r := result.(type) {
+	case *mcp.ListToolsResult:
+		return &r.NextCursor
+	case *mcp.ListResourcesResult:
+		return &r.NextCursor
+	case *mcp.ListResourceTemplatesResult:
+		return &r.NextCursor
+	case *mcp.ListPromptsResult:
+		return &r.NextCursor
+	}
+	return nil
+}
+
+// newCursorMiddleware breaks pagination the way CURSOR_MODE asks. In
+// invalid mode every next cursor is replaced with one the SDK can't
+// decode, so following it fails with -32602. In expired mode the cursors
+// are real, but any request that presents one fails with -32602 and a
+// message saying it expired, as from a server that lost its pagination
+// state between pages. Either way the first page is served normally.
+func newCursorMiddleware(mode string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if mode == cursorModeValid || !isListMethod(method) {
+				return next(ctx, method, req)
+			}
+			if cursor := listCursor(req); mode == cursorModeExpired && cursor != "" {
+				return nil, &jsonrpc.Error{
+					Code:    jsonrpc.CodeInvalidParams,
+					Message: fmt.Sprintf("cursor %q has expired; restart the listing without a cursor", cursor),
+				}
+			}
+			result, err := next(ctx, method, req)
+			if err != nil {
+				return result, err
+			}
+			if c := nextCursor(result); mode == cursorModeInvalid && c != nil && *c != "" {
+				*c = invalidCursor
+			}
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePaginationConfig(t *testing.T) {
+	assert.NoError(t, validatePaginationConfig(0, 0, cursorModeValid))
+	assert.NoError(t, validatePaginationConfig(maxSyntheticItems, 1, cursorModeExpired))
+	assert.ErrorContains(t, validatePaginationConfig(maxSyntheticItems+1, 0, cursorModeValid), "SYNTHETIC_ITEMS")
+	assert.ErrorContains(t, validatePaginationConfig(0, -1, cursorModeValid), "PAGE_SIZE")
+	assert.ErrorContains(t, validatePaginationConfig(0, 0, "stale"), "unknown CURSOR_MODE")
+}
+
+func connectPaginated(ctx context.Context, t *testing.T, items, pageSize int, mode string) *mcp.ClientSession {
+	t.Helper()
+	return connectInMemory(ctx, t, &mcp.ServerOptions{PageSize: pageSize}, func(server *mcp.Server) {
+		addSyntheticItems(server, items)
+		server.AddReceivingMiddleware(newCursorMiddleware(mode))
+	}, nil)
+}
+
+// TestSyntheticItems_Pages follows the cursors through every list and
+// checks each item comes exactly once, in order.
+func TestSyntheticItems_Pages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectPaginated(ctx, t, 20, 7, cursorModeValid)
+
+	var tools, resources, prompts []string
+	var pages [3]int
+	for cursor := ""; pages[0] == 0 || cursor != ""; pages[0]++ {
+		res, err := session.ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+		require.NoError(t, err)
+		for _, tool := range res.Tools {
+			tools = append(tools, tool.Name)
+		}
+		cursor = res.NextCursor
+	}
+	for cursor := ""; pages[1] == 0 || cursor != ""; pages[1]++ {
+		res, err := session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+		require.NoError(t, err)
+		for _, r := range res.Resources {
+			resources = append(resources, r.Name)
+		}
+		cursor = res.NextCursor
+	}
+	for cursor := ""; pages[2] == 0 || cursor != ""; pages[2]++ {
+		res, err := session.ListPrompts(ctx, &mcp.ListPromptsParams{Cursor: cursor})
+		require.NoError(t, err)
+		for _, p := range res.Prompts {
+			prompts = append(prompts, p.Name)
+		}
+		cursor = res.NextCursor
+	}
+
+	assert.Equal(t, [3]int{3, 3, 3}, pages)
+	require.Len(t, tools, 20)
+	assert.Equal(t, "synthetic_00001", tools[0])
+	assert.Equal(t, "synthetic_00020", tools[19])
+	assert.Equal(t, tools, resources)
+	assert.Equal(t, tools, prompts)
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "synthetic_00012", Arguments: map[string]any{}})
+	require.NoError(t, err)
+	assert.Equal(t, "synthetic item 12", result.Content[0].(*mcp.TextContent).Text)
+	read, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "yardstick://synthetic/synthetic_00012"})
+	require.NoError(t, err)
+	assert.Equal(t, "synthetic item 12", read.Contents[0].Text)
+}
+
+func TestCursorMiddleware(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run(cursorModeInvalid, func(t *testing.T) {
+		session := connectPaginated(ctx, t, 10, 4, cursorModeInvalid)
+		res, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+		require.NoError(t, err)
+		assert.Len(t, res.Tools, 4)
+		assert.Equal(t, invalidCursor, res.NextCursor)
+		_, err = session.ListTools(ctx, &mcp.ListToolsParams{Cursor: res.NextCursor})
+		assert.ErrorContains(t, err, "invalid params")
+
+		prompts, err := session.ListPrompts(ctx, &mcp.ListPromptsParams{})
+		require.NoError(t, err)
+		assert.Equal(t, invalidCursor, prompts.NextCursor)
+	})
+
+	t.Run(cursorModeExpired, func(t *testing.T) {
+		session := connectPaginated(ctx, t, 10, 4, cursorModeExpired)
+		res, err := session.ListResources(ctx, &mcp.ListResourcesParams{})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.NextCursor)
+		assert.NotEqual(t, invalidCursor, res.NextCursor)
+		_, err = session.ListResources(ctx, &mcp.ListResourcesParams{Cursor: res.NextCursor})
+		assert.ErrorContains(t, err, "has expired")
+	})
+
+	t.Run(cursorModeValid, func(t *testing.T) {
+		session := connectPaginated(ctx, t, 3, 4, cursorModeValid)
+		res, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+		require.NoError(t, err)
+		assert.Len(t, res.Tools, 3)
+		assert.Empty(t, res.NextCursor)
+	})
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	payloadText       = "text"
+	payloadStructured = "structured"
+	payloadBlob       = "blob"
+
+	// maxPayloadBytes bounds size_bytes. It is well past the body-size
+	// limits gateways commonly default to, while keeping a single call from
+	// exhausting the server's memory.
+	maxPayloadBytes = 64 << 20
+
+	// payloadAlphabet is what text and structured payloads are drawn from:
+	// the base64url characters, so the payload needs no JSON escaping and
+	// one payload byte is one byte on the wire.
+	payloadAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// PayloadRequest represents the request for the payload tool
+type PayloadRequest struct {
+	SizeBytes int    `json:"size_bytes"`
+	Seed      uint64 `json:"seed,omitempty"`
+	Format    string `json:"format,omitempty"`
+}
+
+// PayloadResponse represents the response from the payload tool. Data is
+// only set for the structured format; the others carry the payload in the
+// result's content instead.
+type PayloadResponse struct {
+	Format    string `json:"format"`
+	SizeBytes int    `json:"size_bytes"`
+	Seed      uint64 `json:"seed"`
+	// SHA256 is the hex digest of the payload bytes: the text itself, or
+	// the blob before base64 encoding.
+	SHA256 string `json:"sha256"`
+	Data   string `json:"data,omitempty"`
+}
+
+// generatePayload returns n deterministic bytes derived from seed. The
+// scheme is simple enough to reimplement anywhere: the concatenation of
+// SHA-256(le64(seed) || le64(i)) for i = 0, 1, ..., truncated to n bytes.
+// When text is set, each byte b is then replaced by payloadAlphabet[b & 63].
+func generatePayload(seed uint64, n int, text bool) []byte {
+	out := make([]byte, 0, n+sha256.Size)
+	var block [16]byte
+	binary.LittleEndian.PutUint64(block[:8], seed)
+	for i := uint64(0); len(out) < n; i++ {
+		binary.LittleEndian.PutUint64(block[8:], i)
+		sum := sha256.Sum256(block[:])
+		out = append(out, sum[:]...)
+	}
+	out = out[:n]
+	if text {
+		for i, b := range out {
+			out[i] = payloadAlphabet[b&63]
+		}
+	}
+	return out
+}
+
+func payloadHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params PayloadRequest,
+) (*mcp.CallToolResult, PayloadResponse, error) {
+	if params.SizeBytes < 0 || params.SizeBytes > maxPayloadBytes {
+		return nil, PayloadResponse{}, fmt.Errorf("size_bytes must be between 0 and %d (got %d)",
+			maxPayloadBytes, params.SizeBytes)
+	}
+	format := params.Format
+	if format == "" {
+		format = payloadText
+	}
+	if format != payloadText && format != payloadStructured && format != payloadBlob {
+		return nil, PayloadResponse{}, fmt.Errorf("format must be one of %s, %s or %s (got %q)",
+			payloadText, payloadStructured, payloadBlob, format)
+	}
+
+	data := generatePayload(params.Seed, params.SizeBytes, format != payloadBlob)
+	sum := sha256.Sum256(data)
+	response := PayloadResponse{
+		Format:    format,
+		SizeBytes: params.SizeBytes,
+		Seed:      params.Seed,
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+	summary := fmt.Sprintf("%d-byte %s payload, seed %d, sha256 %s", response.SizeBytes, format, response.Seed,
+		response.SHA256)
+
+	// Each format carries the payload exactly once, so the response size
+	// tracks size_bytes. That's why the structured format's content is a
+	// summary rather than the usual serialization of the structured output.
+	var content mcp.Content
+	switch format {
+	case payloadText:
+		content = &mcp.TextContent{Text: string(data)}
+	case payloadStructured:
+		response.Data = string(data)
+		content = &mcp.TextContent{Text: summary}
+	case payloadBlob:
+		content = &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+			URI:      fmt.Sprintf("yardstick://payload/%d/%d", response.Seed, response.SizeBytes),
+			MIMEType: "application/octet-stream",
+			Blob:     data,
+		}}
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{content}}, response, nil
+}
+
+// addPayloadTool registers payload, whose response size is set by the
+// caller rather than bounded by the size of its input as echo's is.
+func addPayloadTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "payload",
+		Description: fmt.Sprintf("Return a deterministic payload of size_bytes bytes (at most %d) generated from seed, "+
+			"as text, as structured content, or as a base64 blob. The structured output reports the payload's SHA-256.",
+			maxPayloadBytes),
+		InputSchema: objectSchema([]string{"size_bytes"}, map[string]*jsonschema.Schema{
+			"size_bytes": boundedProp("integer", "Payload size in bytes (for blob, before base64 encoding)",
+				0, maxPayloadBytes, nil),
+			"seed": {Type: "integer", Minimum: new(float64), Default: rawDefault(0),
+				Description: "Seed the payload is generated from"},
+			"format": enumProp("text returns the payload as text content; structured in the output's data field; "+
+				"blob as an embedded base64 resource", payloadText, payloadText, payloadStructured, payloadBlob),
+		}),
+	}, payloadHandler)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TestGeneratePayload pins the generator to values computed by an
+// independent implementation of the documented scheme.
+func TestGeneratePayload(t *testing.T) {
+	assert.Equal(t, "u9ODe5P-UhZlW3sgpSAhYflxyx29ZDCjJTyDC_AN", string(generatePayload(42, 40, true)))
+	assert.Equal(t, "f44e7b530587126b6bbb2b6ec25ff904da4db3ce96e09e11e8eeae143d3709e9",
+		sha256Hex(generatePayload(42, 1000, false)))
+	assert.Equal(t, "6b140ebfeab2fd41415b37c623ad580548febde32ea0315bcfc4c43c764224d3",
+		sha256Hex(generatePayload(7, 1<<20, true)))
+
+	assert.Empty(t, generatePayload(1, 0, true))
+	assert.NotEqual(t, generatePayload(1, 64, true), generatePayload(2, 64, true))
+}
+
+func TestPayloadHandler(t *testing.T) {
+	tests := []struct {
+		format  string
+		size    int
+		wantErr string
+	}{
+		{format: "", size: 100},
+		{format: payloadText, size: 0},
+		{format: payloadStructured, size: 4097},
+		{format: payloadBlob, size: 33},
+		{format: "xml", size: 10, wantErr: "format must be one of"},
+		{format: payloadText, size: -1, wantErr: "size_bytes must be between"},
+		{format: payloadText, size: maxPayloadBytes + 1, wantErr: "size_bytes must be between"},
+	}
+
+	for _, tt := range tests {
+		result, got, err := payloadHandler(context.Background(), nil,
+			PayloadRequest{SizeBytes: tt.size, Seed: 9, Format: tt.format})
+		if tt.wantErr != "" {
+			assert.ErrorContains(t, err, tt.wantErr, "%s/%d", tt.format, tt.size)
+			continue
+		}
+		require.NoError(t, err, "%s/%d", tt.format, tt.size)
+		require.Len(t, result.Content, 1)
+		assert.Equal(t, tt.size, got.SizeBytes)
+
+		var data []byte
+		switch got.Format {
+		case payloadText:
+			data = []byte(result.Content[0].(*mcp.TextContent).Text)
+			assert.Empty(t, got.Data)
+		case payloadStructured:
+			data = []byte(got.Data)
+			assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, got.SHA256)
+		case payloadBlob:
+			resource := result.Content[0].(*mcp.EmbeddedResource).Resource
+			assert.Equal(t, "application/octet-stream", resource.MIMEType)
+			data = resource.Blob
+		}
+		assert.Len(t, data, tt.size)
+		assert.Equal(t, sha256Hex(data), got.SHA256)
+	}
+}
+
+// TestPayload_LargeOverHTTP pulls a multi-megabyte payload in each format
+// through each HTTP-based transport and verifies it by hash.
+func TestPayload_LargeOverHTTP(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addPayloadTool(server)
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	const size = 8 << 20
+	transports := map[string]func() mcp.Transport{
+		transportSSE: func() mcp.Transport { return &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath} },
+		transportStreamableHTTP: func() mcp.Transport {
+			return &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath}
+		},
+		transportWebSocket: func() mcp.Transport { return &wsconn.ClientTransport{URL: httpServer.URL + wsPath} },
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport(), nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			for _, format := range []string{payloadText, payloadStructured, payloadBlob} {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{
+					Name:      "payload",
+					Arguments: map[string]any{"size_bytes": size, "seed": 3, "format": format},
+				})
+				require.NoError(t, err, format)
+				require.False(t, result.IsError, format)
+
+				want := sha256Hex(generatePayload(3, size, format != payloadBlob))
+				structured := result.StructuredContent.(map[string]any)
+				assert.Equal(t, want, structured["sha256"], format)
+
+				var data []byte
+				switch format {
+				case payloadText:
+					data = []byte(result.Content[0].(*mcp.TextContent).Text)
+				case payloadStructured:
+					data = []byte(structured["data"].(string))
+				case payloadBlob:
+					data = result.Content[0].(*mcp.EmbeddedResource).Resource.Blob
+				}
+				assert.Equal(t, want, sha256Hex(data), format)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultProgressSteps    = 5
+	maxProgressSteps        = 1000
+	defaultProgressInterval = 100
+	maxProgressInterval     = 60000
+)
+
+// ProgressRequest represents the request for the progress tool
+type ProgressRequest struct {
+	Steps      int `json:"steps,omitempty"`
+	IntervalMS int `json:"interval_ms,omitempty"`
+	// Indeterminate leaves total out of the notifications, as a server
+	// does when it can't tell how much work remains.
+	Indeterminate bool `json:"indeterminate,omitempty"`
+}
+
+// ProgressResponse represents the response from the progress tool
+type ProgressResponse struct {
+	Steps int `json:"steps"`
+	// Notified is how many progress notifications were sent: Steps if the
+	// caller supplied a progressToken, otherwise zero.
+	Notified      int `json:"notified"`
+	ProgressToken any `json:"progress_token,omitempty"`
+}
+
+// progressHandler works through params.Steps steps, one every
+// params.IntervalMS, sending a notifications/progress after each. The
+// notifications are sent in the context of the call, so on streamable HTTP
+// they travel on the call's own response stream.
+func progressHandler(
+	ctx context.Context, req *mcp.CallToolRequest, params ProgressRequest,
+) (*mcp.CallToolResult, ProgressResponse, error) {
+	steps, interval := params.Steps, params.IntervalMS
+	if steps == 0 {
+		steps = defaultProgressSteps
+	}
+	if steps < 1 || steps > maxProgressSteps {
+		return nil, ProgressResponse{}, fmt.Errorf("steps must be between 1 and %d (got %d)", maxProgressSteps, steps)
+	}
+	if interval < 0 || interval > maxProgressInterval {
+		return nil, ProgressResponse{}, fmt.Errorf("interval_ms must be between 0 and %d (got %d)",
+			maxProgressInterval, interval)
+	}
+
+	token := req.Params.GetProgressToken()
+	response := ProgressResponse{Steps: steps, ProgressToken: token}
+	total := float64(steps)
+	if params.Indeterminate {
+		total = 0
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for step := 1; step <= steps; step++ {
+		if tick != nil {
+			select {
+			case <-ctx.Done():
+				return nil, ProgressResponse{}, ctx.Err()
+			case <-tick:
+			}
+		}
+		if token == nil {
+			continue
+		}
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(step),
+			Total:         total,
+			Message:       fmt.Sprintf("step %d of %d", step, steps),
+		})
+		if err != nil {
+			log.Printf("progress: failed to notify step %d of %d: %v", step, steps, err)
+			continue
+		}
+		response.Notified++
+	}
+	return nil, response, nil
+}
+
+func addProgressTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "progress",
+		Description: "Run for steps × interval_ms, sending a notifications/progress with progress, total and message " +
+			"after each step to the caller's progressToken. Without a progressToken no notifications are sent.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"steps": boundedProp("integer", "Number of steps, and so of notifications", 1, maxProgressSteps,
+				defaultProgressSteps),
+			"interval_ms": boundedProp("integer", "Milliseconds to wait before each step", 0, maxProgressInterval,
+				defaultProgressInterval),
+			"indeterminate": {Type: "boolean", Default: rawDefault(false),
+				Description: "Leave total out of the notifications"},
+		}),
+	}, progressHandler)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+func TestProgressHandler_Bounds(t *testing.T) {
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}}
+	_, _, err := progressHandler(context.Background(), req, ProgressRequest{Steps: maxProgressSteps + 1})
+	assert.ErrorContains(t, err, "steps")
+	_, _, err = progressHandler(context.Background(), req, ProgressRequest{IntervalMS: -1})
+	assert.ErrorContains(t, err, "interval_ms")
+
+	// Without a progressToken the steps still run, but nothing is sent.
+	_, got, err := progressHandler(context.Background(), req, ProgressRequest{Steps: 3})
+	require.NoError(t, err)
+	assert.Equal(t, ProgressResponse{Steps: 3}, got)
+}
+
+func TestProgressHandler_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{}}
+	_, _, err := progressHandler(ctx, req, ProgressRequest{Steps: 3, IntervalMS: 1000})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// TestProgress_Transports checks every notification reaches the caller,
+// in order and tied to its token, over each HTTP-based transport.
+func TestProgress_Transports(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addProgressTool(server)
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	transports := map[string]func() mcp.Transport{
+		transportSSE: func() mcp.Transport { return &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath} },
+		transportStreamableHTTP: func() mcp.Transport {
+			return &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath}
+		},
+		transportWebSocket: func() mcp.Transport { return &wsconn.ClientTransport{URL: httpServer.URL + wsPath} },
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var got []*mcp.ProgressNotificationParams
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, &mcp.ClientOptions{
+				ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+					mu.Lock()
+					defer mu.Unlock()
+					got = append(got, req.Params)
+				},
+			})
+			session, err := client.Connect(ctx, transport(), nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			params := &mcp.CallToolParams{Name: "progress", Arguments: map[string]any{"steps": 4, "interval_ms": 10}}
+			params.SetProgressToken("tok-" + name)
+			result, err := session.CallTool(ctx, params)
+			require.NoError(t, err)
+			require.False(t, result.IsError, "%v", result.Content)
+			assert.Equal(t, 4.0, result.StructuredContent.(map[string]any)["notified"])
+
+			// Notifications may be handled just after the result arrives.
+			require.Eventually(t, func() bool {
+				mu.Lock()
+				defer mu.Unlock()
+				return len(got) == 4
+			}, time.Second, 10*time.Millisecond)
+			mu.Lock()
+			defer mu.Unlock()
+			for i, p := range got {
+				assert.Equal(t, "tok-"+name, p.ProgressToken)
+				assert.Equal(t, float64(i+1), p.Progress)
+				assert.Equal(t, 4.0, p.Total)
+				assert.NotEmpty(t, p.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// echoHelperSkillURI is where the echo_helper prompt's instructions can
+	// also be read as a resource.
+	echoHelperSkillURI = "yardstick://skills/echo-helper/SKILL.md"
+	// echoHelperSkill is skills/echo-helper/SKILL.md, which the server can't
+	// embed from outside its package; a test keeps the two in sync.
+	echoHelperSkill = "# Echo Helper\n\nYou are an echo helper skill. When the user asks you to echo text, " +
+		"use the echo tool to echo it back verbatim. Then confirm what you echoed.\n"
+
+	defaultConversationTurns = 2
+	maxConversationTurns     = 10
+)
+
+// promptArgs checks a prompts/get request's arguments against the
+// prompt's: every required one must be non-empty and no others may be
+// given, so a gateway that drops or renames an argument gets an error
+// rather than a different rendering.
+func promptArgs(prompt *mcp.Prompt, req *mcp.GetPromptRequest) (map[string]string, error) {
+	args := req.Params.Arguments
+	if args == nil {
+		args = map[string]string{}
+	}
+	for name := range args {
+		if !slices.ContainsFunc(prompt.Arguments, func(a *mcp.PromptArgument) bool { return a.Name == name }) {
+			return nil, invalidPromptArgs(prompt.Name, "unknown argument %q", name)
+		}
+	}
+	for _, a := range prompt.Arguments {
+		if a.Required && args[a.Name] == "" {
+			return nil, invalidPromptArgs(prompt.Name, "argument %q is required", a.Name)
+		}
+	}
+	return args, nil
+}
+
+func invalidPromptArgs(name, format string, args ...any) error {
+	return &jsonrpc.Error{
+		Code:    jsonrpc.CodeInvalidParams,
+		Message: fmt.Sprintf("invalid arguments for prompt %q: %s", name, fmt.Sprintf(format, args...)),
+	}
+}
+
+func textMessage(role mcp.Role, text string) *mcp.PromptMessage {
+	return &mcp.PromptMessage{Role: role, Content: &mcp.TextContent{Text: text}}
+}
+
+// promptHandler checks a request's arguments before passing them to
+// render.
+func promptHandler(prompt *mcp.Prompt, render func(args map[string]string) (*mcp.GetPromptResult, error)) mcp.PromptHandler {
+	return func(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args, err := promptArgs(prompt, req)
+		if err != nil {
+			return nil, err
+		}
+		return render(args)
+	}
+}
+
+var echoHelperPrompt = &mcp.Prompt{
+	Name:        "echo_helper",
+	Title:       "Echo Helper",
+	Description: "Ask the model to echo text with the echo tool, following the echo-helper skill",
+	Arguments: []*mcp.PromptArgument{
+		{Name: "text", Description: "Text to echo", Required: true},
+	},
+}
+
+// renderEchoHelper embeds the skill's instructions, then asks for the
+// text to be echoed and answers as the skill would.
+func renderEchoHelper(args map[string]string) (*mcp.GetPromptResult, error) {
+	text := args["text"]
+	return &mcp.GetPromptResult{
+		Description: fmt.Sprintf("Echo %q with the echo tool", text),
+		Messages: []*mcp.PromptMessage{
+			{Role: "user", Content: &mcp.EmbeddedResource{Resource: &mcp.ResourceContents{
+				URI: echoHelperSkillURI, MIMEType: mimeMarkdown, Text: echoHelperSkill,
+			}}},
+			textMessage("user", "Please echo: "+text),
+			textMessage("assistant", fmt.Sprintf("I'll call the echo tool with %q, then confirm what I echoed.", text)),
+		},
+	}, nil
+}
+
+var reviewResourcePrompt = &mcp.Prompt{
+	Name:        "review_resource",
+	Title:       "Review Resource",
+	Description: "Ask the model to review one of the server's static resources, which is embedded in the prompt",
+	Arguments: []*mcp.PromptArgument{
+		{Name: "uri", Description: "URI of a static resource, e.g. yardstick://static/hello.txt", Required: true},
+		{Name: "focus", Description: "What the review should focus on"},
+	},
+}
+
+// renderReviewResource embeds the resource between a request to review it
+// and an answer giving its size, then asks for a summary.
+func renderReviewResource(args map[string]string) (*mcp.GetPromptResult, error) {
+	uri := args["uri"]
+	i := slices.IndexFunc(staticResources(), func(r staticResource) bool { return r.resource.URI == uri })
+	if i < 0 {
+		return nil, invalidPromptArgs(reviewResourcePrompt.Name, "%q is not a static resource", uri)
+	}
+	r := staticResources()[i]
+	contents, err := r.contents()
+	if err != nil {
+		return nil, err
+	}
+	contents.URI, contents.MIMEType = uri, r.resource.MIMEType
+
+	request := "Review the resource below."
+	if focus := args["focus"]; focus != "" {
+		request = fmt.Sprintf("Review the resource below, focusing on %s.", focus)
+	}
+	return &mcp.GetPromptResult{
+		Description: "Review " + uri,
+		Messages: []*mcp.PromptMessage{
+			textMessage("user", request),
+			{Role: "user", Content: &mcp.EmbeddedResource{Resource: contents}},
+			textMessage("assistant", fmt.Sprintf("I've read %s (%s, %d bytes).",
+				uri, contents.MIMEType, len(contents.Text)+len(contents.Blob))),
+			textMessage("user", "Summarize it in one sentence."),
+		},
+	}, nil
+}
+
+var conversationPrompt = &mcp.Prompt{
+	Name:        "conversation",
+	Title:       "Conversation",
+	Description: "A scripted exchange of alternating user and assistant messages about a topic",
+	Arguments: []*mcp.PromptArgument{
+		{Name: "topic", Description: "What the conversation is about", Required: true},
+		{Name: "turns", Description: fmt.Sprintf("Number of question and answer pairs, 1-%d (default %d)",
+			maxConversationTurns, defaultConversationTurns)},
+	},
+}
+
+// renderConversation numbers each question and answer, so a gateway that
+// drops, merges or reorders messages is easy to spot.
+func renderConversation(args map[string]string) (*mcp.GetPromptResult, error) {
+	topic, turns := args["topic"], defaultConversationTurns
+	if s := args["turns"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxConversationTurns {
+			return nil, invalidPromptArgs(conversationPrompt.Name,
+				"turns must be an integer between 1 and %d (got %q)", maxConversationTurns, s)
+		}
+		turns = n
+	}
+	result := &mcp.GetPromptResult{Description: fmt.Sprintf("%d turn(s) about %s", turns, topic)}
+	for i := 1; i <= turns; i++ {
+		result.Messages = append(result.Messages,
+			textMessage("user", fmt.Sprintf("Question %d about %s?", i, topic)),
+			textMessage("assistant", fmt.Sprintf("Answer %d about %s.", i, topic)))
+	}
+	return result, nil
+}
+
+// addPrompts registers the fixed prompts. Each renders the same messages
+// for the same arguments.
+func addPrompts(server *mcp.Server) {
+	server.AddPrompt(echoHelperPrompt, promptHandler(echoHelperPrompt, renderEchoHelper))
+	server.AddPrompt(reviewResourcePrompt, promptHandler(reviewResourcePrompt, renderReviewResource))
+	server.AddPrompt(conversationPrompt, promptHandler(conversationPrompt, renderConversation))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func connectPrompts(ctx context.Context, t *testing.T) *mcp.ClientSession {
+	t.Helper()
+	return connectInMemory(ctx, t, nil, addPrompts, nil)
+}
+
+// TestEchoHelperSkill keeps the echo_helper prompt in sync with the skill
+// it was taken from.
+func TestEchoHelperSkill(t *testing.T) {
+	skill, err := os.ReadFile("../../skills/echo-helper/SKILL.md")
+	require.NoError(t, err)
+	assert.Equal(t, string(skill), echoHelperSkill)
+}
+
+func TestPrompts_List(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectPrompts(ctx, t)
+
+	result, err := session.ListPrompts(ctx, &mcp.ListPromptsParams{})
+	require.NoError(t, err)
+	required := map[string][]string{}
+	for _, p := range result.Prompts {
+		required[p.Name] = []string{}
+		for _, a := range p.Arguments {
+			if a.Required {
+				required[p.Name] = append(required[p.Name], a.Name)
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"conversation":    {"topic"},
+		"echo_helper":     {"text"},
+		"review_resource": {"uri"},
+	}, required)
+}
+
+func TestPrompts_Get(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectPrompts(ctx, t)
+
+	type message struct {
+		role string
+		text string
+		uri  string
+	}
+	tests := []struct {
+		name     string
+		args     map[string]string
+		messages []message
+	}{
+		{
+			name: "echo_helper",
+			args: map[string]string{"text": "hello"},
+			messages: []message{
+				{role: "user", text: echoHelperSkill, uri: echoHelperSkillURI},
+				{role: "user", text: "Please echo: hello"},
+				{role: "assistant", text: `I'll call the echo tool with "hello", then confirm what I echoed.`},
+			},
+		},
+		{
+			name: "review_resource",
+			args: map[string]string{"uri": "yardstick://static/hello.txt"},
+			messages: []message{
+				{role: "user", text: "Review the resource below."},
+				{role: "user", text: "Hello from yardstick.\n", uri: "yardstick://static/hello.txt"},
+				{role: "assistant", text: "I've read yardstick://static/hello.txt (text/plain, 22 bytes)."},
+				{role: "user", text: "Summarize it in one sentence."},
+			},
+		},
+		{
+			name: "review_resource",
+			args: map[string]string{"uri": "yardstick://static/bytes.bin", "focus": "byte order"},
+			messages: []message{
+				{role: "user", text: "Review the resource below, focusing on byte order."},
+				{role: "user", uri: "yardstick://static/bytes.bin"},
+				{role: "assistant", text: "I've read yardstick://static/bytes.bin (application/octet-stream, 256 bytes)."},
+				{role: "user", text: "Summarize it in one sentence."},
+			},
+		},
+		{
+			name: "conversation",
+			args: map[string]string{"topic": "rulers"},
+			messages: []message{
+				{role: "user", text: "Question 1 about rulers?"},
+				{role: "assistant", text: "Answer 1 about rulers."},
+				{role: "user", text: "Question 2 about rulers?"},
+				{role: "assistant", text: "Answer 2 about rulers."},
+			},
+		},
+		{
+			name: "conversation",
+			args: map[string]string{"topic": "rulers", "turns": "1"},
+			messages: []message{
+				{role: "user", text: "Question 1 about rulers?"},
+				{role: "assistant", text: "Answer 1 about rulers."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		result, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: tt.name, Arguments: tt.args})
+		require.NoError(t, err, "%s %v", tt.name, tt.args)
+		assert.NotEmpty(t, result.Description)
+		var got []message
+		for _, m := range result.Messages {
+			msg := message{role: string(m.Role)}
+			switch c := m.Content.(type) {
+			case *mcp.TextContent:
+				msg.text = c.Text
+			case *mcp.EmbeddedResource:
+				msg.text, msg.uri = c.Resource.Text, c.Resource.URI
+			default:
+				t.Fatalf("unexpected content %T", c)
+			}
+			got = append(got, msg)
+		}
+		assert.Equal(t, tt.messages, got, "%s %v", tt.name, tt.args)
+	}
+}
+
+func TestPrompts_InvalidArguments(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectPrompts(ctx, t)
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		wantErr string
+	}{
+		{"echo_helper", nil, `argument "text" is required`},
+		{"echo_helper", map[string]string{"text": ""}, `argument "text" is required`},
+		{"echo_helper", map[string]string{"text": "hi", "input": "hi"}, `unknown argument "input"`},
+		{"review_resource", map[string]string{"uri": "yardstick://echo/hi"}, "is not a static resource"},
+		{"conversation", map[string]string{"topic": "x", "turns": "11"}, "turns must be an integer between 1 and 10"},
+		{"conversation", map[string]string{"topic": "x", "turns": "two"}, "turns must be an integer"},
+	}
+	for _, tt := range tests {
+		_, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: tt.name, Arguments: tt.args})
+		var rpcErr *jsonrpc.Error
+		require.True(t, errors.As(err, &rpcErr), "%s %v: %v", tt.name, tt.args, err)
+		assert.Equal(t, int64(jsonrpc.CodeInvalidParams), rpcErr.Code)
+		assert.Contains(t, rpcErr.Message, tt.wantErr)
+	}
+}
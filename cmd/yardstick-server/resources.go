@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	echoResourcePrefix    = "yardstick://echo/"
+	payloadResourcePrefix = "yardstick://payload/"
+
+	echoResourceTemplate    = echoResourcePrefix + "{value}"
+	payloadResourceTemplate = payloadResourcePrefix + "{seed}/{size}"
+
+	mimeJSON     = "application/json"
+	mimeMarkdown = "text/markdown"
+	mimeOctets   = "application/octet-stream"
+
+	staticJSON = `{"name":"yardstick","numbers":[1,2,3],"nested":{"ok":true,"empty":null}}` + "\n"
+	// staticMarkdown carries multi-byte UTF-8, so a gateway that mangles
+	// encodings changes its length as well as its bytes.
+	staticMarkdown = "# Yardstick\n\nDeterministic résumé of naïve façades: ünïcödé, 日本語, emoji 📏.\n"
+)
+
+// staticResource is a resource whose contents never change.
+type staticResource struct {
+	resource *mcp.Resource
+	contents func() (*mcp.ResourceContents, error)
+}
+
+// allBytes returns every byte value once, in order, so a blob that is
+// truncated, re-encoded or stripped of control bytes is easy to spot.
+func allBytes() []byte {
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+func textContents(text string) func() (*mcp.ResourceContents, error) {
+	return func() (*mcp.ResourceContents, error) { return &mcp.ResourceContents{Text: text}, nil }
+}
+
+// staticResources lists the fixed resources, including those the media
+// tools and the prompts embed or link to, so every URI they hand out can
+// be read.
+func staticResources() []staticResource {
+	return []staticResource{
+		{
+			resource: &mcp.Resource{URI: "yardstick://static/hello.txt", Name: "hello.txt", MIMEType: mimeText,
+				Description: "A one-line plain text document"},
+			contents: textContents("Hello from yardstick.\n"),
+		},
+		{
+			resource: &mcp.Resource{URI: "yardstick://static/data.json", Name: "data.json", MIMEType: mimeJSON,
+				Description: "A small JSON document, served as text"},
+			contents: textContents(staticJSON),
+		},
+		{
+			resource: &mcp.Resource{URI: "yardstick://static/unicode.md", Name: "unicode.md", MIMEType: mimeMarkdown,
+				Description: "A Markdown document with multi-byte UTF-8 characters"},
+			contents: textContents(staticMarkdown),
+		},
+		{
+			resource: &mcp.Resource{URI: "yardstick://static/bytes.bin", Name: "bytes.bin", MIMEType: mimeOctets,
+				Description: "Every byte value from 0x00 to 0xff in order, as a blob"},
+			contents: func() (*mcp.ResourceContents, error) { return &mcp.ResourceContents{Blob: allBytes()}, nil },
+		},
+		{
+			resource: &mcp.Resource{URI: echoHelperSkillURI, Name: "SKILL.md", MIMEType: mimeMarkdown,
+				Description: "The echo-helper skill, which the echo_helper prompt embeds"},
+			contents: textContents(echoHelperSkill),
+		},
+		{
+			resource: &mcp.Resource{URI: mediaTextURI, Name: "readme.txt", MIMEType: mimeText,
+				Description: "The text the embedded_resource tool embeds"},
+			contents: textContents(mediaText),
+		},
+		{
+			resource: &mcp.Resource{URI: mediaImageURI, Name: "image.png", MIMEType: mimePNG,
+				Description: fmt.Sprintf("The image tool's default %dx%d gradient", defaultImageSide, defaultImageSide)},
+			contents: func() (*mcp.ResourceContents, error) {
+				img, err := imageContent(0, 0)
+				if err != nil {
+					return nil, err
+				}
+				return &mcp.ResourceContents{Blob: img.Data}, nil
+			},
+		},
+		{
+			resource: &mcp.Resource{URI: mediaAudioURI, Name: "tone.wav", MIMEType: mimeWAV,
+				Description: fmt.Sprintf("The audio tool's default %d ms, %d Hz tone", defaultToneMillis, defaultToneHz)},
+			contents: func() (*mcp.ResourceContents, error) {
+				audio, err := audioContent(0, 0)
+				if err != nil {
+					return nil, err
+				}
+				return &mcp.ResourceContents{Blob: audio.Data}, nil
+			},
+		},
+	}
+}
+
+// resourceHandler serves contents for any URI the SDK routes to it. The
+// SDK fills in the URI and the registered MIME type.
+func resourceHandler(contents func() (*mcp.ResourceContents, error)) mcp.ResourceHandler {
+	return func(_ context.Context, _ *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		c, err := contents()
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{c}}, nil
+	}
+}
+
+func invalidResource(uri, format string, args ...any) error {
+	return &jsonrpc.Error{
+		Code:    jsonrpc.CodeInvalidParams,
+		Message: fmt.Sprintf("invalid resource URI %q: %s", uri, fmt.Sprintf(format, args...)),
+	}
+}
+
+// echoResourceHandler serves yardstick://echo/{value}, returning the
+// value percent-decoded, so a gateway that re-encodes or normalizes URIs
+// shows up as a changed value.
+func echoResourceHandler(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	value, err := url.PathUnescape(strings.TrimPrefix(uri, echoResourcePrefix))
+	if err != nil {
+		return nil, invalidResource(uri, "%v", err)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: mimeText, Text: value}}}, nil
+}
+
+// payloadResourceHandler serves yardstick://payload/{seed}/{size}: the
+// same bytes the payload tool returns as a blob for that seed and size, at
+// the URI it gives them.
+func payloadResourceHandler(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	seedText, sizeText, _ := strings.Cut(strings.TrimPrefix(uri, payloadResourcePrefix), "/")
+	seed, err := strconv.ParseUint(seedText, 10, 64)
+	if err != nil {
+		return nil, invalidResource(uri, "seed must be an unsigned integer (got %q)", seedText)
+	}
+	size, err := strconv.Atoi(sizeText)
+	if err != nil || size < 0 || size > maxPayloadBytes {
+		return nil, invalidResource(uri, "size must be between 0 and %d (got %q)", maxPayloadBytes, sizeText)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+		URI: uri, MIMEType: mimeOctets, Blob: generatePayload(seed, size, false),
+	}}}, nil
+}
+
+// addResources registers the static resources and the resource templates.
+// Every resource is deterministic, so a read can be compared byte for byte
+// with a direct one.
+func addResources(server *mcp.Server) {
+	for _, r := range staticResources() {
+		// Contents are generated on every read, like the media tools', but
+		// reading them once here lets the listing give each one's size.
+		if c, err := r.contents(); err == nil {
+			r.resource.Size = int64(len(c.Text) + len(c.Blob))
+		}
+		server.AddResource(r.resource, resourceHandler(r.contents))
+	}
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: echoResourceTemplate,
+		Name:        "echo",
+		MIMEType:    mimeText,
+		Description: "Returns the percent-decoded {value} as text",
+	}, echoResourceHandler)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: payloadResourceTemplate,
+		Name:        "payload",
+		MIMEType:    mimeOctets,
+		Description: fmt.Sprintf("Returns the payload tool's blob for {seed} and {size} (at most %d bytes)",
+			maxPayloadBytes),
+	}, payloadResourceHandler)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+func connectResources(ctx context.Context, t *testing.T) *mcp.ClientSession {
+	t.Helper()
+	return connectInMemory(ctx, t, nil, addResources, nil)
+}
+
+func TestResources_List(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectResources(ctx, t)
+
+	resources, err := session.ListResources(ctx, &mcp.ListResourcesParams{})
+	require.NoError(t, err)
+	require.Len(t, resources.Resources, len(staticResources()))
+	sizes := map[string]int64{}
+	for _, r := range resources.Resources {
+		sizes[r.URI] = r.Size
+		assert.NotEmpty(t, r.MIMEType, r.URI)
+	}
+	assert.Equal(t, int64(256), sizes["yardstick://static/bytes.bin"])
+	assert.Equal(t, int64(len(mediaText)), sizes[mediaTextURI])
+
+	templates, err := session.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
+	require.NoError(t, err)
+	var uriTemplates []string
+	for _, rt := range templates.ResourceTemplates {
+		uriTemplates = append(uriTemplates, rt.URITemplate)
+	}
+	assert.ElementsMatch(t, []string{"yardstick://echo/{value}", "yardstick://payload/{seed}/{size}"}, uriTemplates)
+}
+
+func TestResources_Read(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectResources(ctx, t)
+
+	img, err := imageContent(0, 0)
+	require.NoError(t, err)
+	tests := []struct {
+		uri  string
+		mime string
+		text string
+		blob []byte
+	}{
+		{uri: "yardstick://static/data.json", mime: mimeJSON, text: staticJSON},
+		{uri: "yardstick://static/unicode.md", mime: mimeMarkdown, text: staticMarkdown},
+		{uri: "yardstick://static/bytes.bin", mime: mimeOctets, blob: allBytes()},
+		{uri: mediaImageURI, mime: mimePNG, blob: img.Data},
+		{uri: "yardstick://echo/hello", mime: mimeText, text: "hello"},
+		{uri: "yardstick://echo/a%20b%2Fc%C3%A9", mime: mimeText, text: "a b/cé"},
+		{uri: "yardstick://payload/7/100", mime: mimeOctets, blob: generatePayload(7, 100, false)},
+		{uri: "yardstick://payload/0/0", mime: mimeOctets, blob: []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tt.uri})
+			require.NoError(t, err)
+			require.Len(t, result.Contents, 1)
+			c := result.Contents[0]
+			assert.Equal(t, tt.uri, c.URI)
+			assert.Equal(t, tt.mime, c.MIMEType)
+			assert.Equal(t, tt.text, c.Text)
+			assert.Equal(t, sha256.Sum256(tt.blob), sha256.Sum256(c.Blob))
+		})
+	}
+}
+
+func TestResources_ReadErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session := connectResources(ctx, t)
+
+	for uri, want := range map[string]string{
+		"yardstick://static/missing.txt": "not found",
+		// The SDK's template matching rejects malformed escapes itself.
+		"yardstick://echo/%zz":            "not found",
+		"yardstick://payload/x/10":        "seed must be",
+		"yardstick://payload/1/999999999": "size must be",
+	} {
+		_, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri})
+		assert.ErrorContains(t, err, want, uri)
+	}
+}
+
+// TestResources_Transports reads a templated blob over each HTTP-based
+// transport.
+func TestResources_Transports(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addResources(server)
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	transports := map[string]func() mcp.Transport{
+		transportSSE: func() mcp.Transport { return &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath} },
+		transportStreamableHTTP: func() mcp.Transport {
+			return &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath}
+		},
+		transportWebSocket: func() mcp.Transport { return &wsconn.ClientTransport{URL: httpServer.URL + wsPath} },
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport(), nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "yardstick://payload/3/65536"})
+			require.NoError(t, err)
+			require.Len(t, result.Contents, 1)
+			assert.Equal(t, sha256.Sum256(generatePayload(3, 65536, false)), sha256.Sum256(result.Contents[0].Blob))
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	notificationRootsListChanged = "notifications/roots/list_changed"
+
+	defaultRootsTimeout = 30000
+	maxRootsTimeout     = 600000
+
+	// rootsInputID is the ID of the roots/list request in a 2026-07-28
+	// input-required result.
+	rootsInputID = "roots"
+)
+
+// ListRootsRequest represents the request for the list_roots tool
+type ListRootsRequest struct {
+	TimeoutMS int `json:"timeout_ms,omitempty"`
+}
+
+// RootEntry is one of the client's roots.
+type RootEntry struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// ListRootsResponse represents the response from the list_roots tool
+type ListRootsResponse struct {
+	Roots []RootEntry `json:"roots"`
+	// ListChanged counts the notifications/roots/list_changed the session
+	// has sent, so a gateway that drops them can be caught.
+	ListChanged int `json:"list_changed"`
+	// RoundTrip and ElapsedMS are as in SampleResponse.
+	RoundTrip string `json:"round_trip"`
+	ElapsedMS int64  `json:"elapsed_ms"`
+}
+
+// rootsTracker counts the notifications/roots/list_changed each session
+// sends.
+type rootsTracker struct {
+	server *mcp.Server
+
+	mu      sync.Mutex
+	changes map[mcp.Session]int
+}
+
+// middleware records each notifications/roots/list_changed before the SDK
+// handles it, dropping the counts of sessions that have ended.
+func (r *rootsTracker) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == notificationRootsListChanged {
+			connected := connectedSessions(r.server)
+			r.mu.Lock()
+			maps.DeleteFunc(r.changes, func(session mcp.Session, _ int) bool { return !connected[session] })
+			r.changes[req.GetSession()]++
+			r.mu.Unlock()
+		}
+		return next(ctx, method, req)
+	}
+}
+
+func (r *rootsTracker) listChanged(session mcp.Session) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.changes[session]
+}
+
+func (r *rootsTracker) response(
+	session mcp.Session, result *mcp.ListRootsResult, roundTrip string, elapsed time.Duration,
+) ListRootsResponse {
+	response := ListRootsResponse{
+		Roots:       []RootEntry{},
+		ListChanged: r.listChanged(session),
+		RoundTrip:   roundTrip,
+		ElapsedMS:   elapsed.Milliseconds(),
+	}
+	for _, root := range result.Roots {
+		response.Roots = append(response.Roots, RootEntry{URI: root.URI, Name: root.Name})
+	}
+	return response
+}
+
+// listHandler asks the client for its roots: with an input request if the
+// client uses them, otherwise with roots/list.
+func (r *rootsTracker) listHandler(
+	ctx context.Context, req *mcp.CallToolRequest, params ListRootsRequest,
+) (*mcp.CallToolResult, ListRootsResponse, error) {
+	if params.TimeoutMS == 0 {
+		params.TimeoutMS = defaultRootsTimeout
+	}
+	if params.TimeoutMS < 1 || params.TimeoutMS > maxRootsTimeout {
+		return nil, ListRootsResponse{}, fmt.Errorf("timeout_ms must be between 1 and %d (got %d)",
+			maxRootsTimeout, params.TimeoutMS)
+	}
+	if caps := req.ClientCapabilities(); caps == nil || caps.RootsV2 == nil {
+		return nil, ListRootsResponse{}, errors.New("client does not support roots: it did not declare the roots capability")
+	}
+
+	if !usesInputRequests(req) {
+		result, elapsed, err := listRootsByRequest(ctx, req.Session, params.TimeoutMS)
+		if err != nil {
+			return nil, ListRootsResponse{}, err
+		}
+		return nil, r.response(req.Session, result, roundTripRequest, elapsed), nil
+	}
+
+	answer, ok := req.Params.InputResponses[rootsInputID]
+	if !ok {
+		return &mcp.CallToolResult{
+			InputRequests: mcp.InputRequestMap{rootsInputID: &mcp.ListRootsParams{}},
+			RequestState:  inputRequestState(),
+		}, ListRootsResponse{}, nil
+	}
+	result, ok := answer.(*mcp.ListRootsResult)
+	if !ok {
+		return nil, ListRootsResponse{}, fmt.Errorf("input response %q is %T, not a roots list", rootsInputID, answer)
+	}
+	elapsed, err := inputElapsed(req, params.TimeoutMS)
+	if err != nil {
+		return nil, ListRootsResponse{}, err
+	}
+	return nil, r.response(req.Session, result, roundTripInputRequired, elapsed), nil
+}
+
+// listRootsByRequest sends roots/list and waits at most timeoutMS for the
+// answer, cancelling the request if it doesn't come.
+func listRootsByRequest(
+	ctx context.Context, session *mcp.ServerSession, timeoutMS int,
+) (*mcp.ListRootsResult, time.Duration, error) {
+	listCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutMS)*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := session.ListRoots(listCtx, &mcp.ListRootsParams{})
+	elapsed := time.Since(start)
+	switch {
+	case err != nil && ctx.Err() == nil && listCtx.Err() != nil:
+		return nil, 0, fmt.Errorf("client did not answer roots/list within %dms", timeoutMS)
+	case err != nil:
+		return nil, 0, fmt.Errorf("roots/list failed: %w", err)
+	}
+	return result, elapsed, nil
+}
+
+// addRootsTool registers list_roots, which lists the client's roots.
+func addRootsTool(server *mcp.Server) {
+	tracker := &rootsTracker{server: server, changes: map[mcp.Session]int{}}
+	server.AddReceivingMiddleware(tracker.middleware)
+
+	addTool(server, &mcp.Tool{
+		Name: "list_roots",
+		Description: "Ask the client for its roots and return them, with how many " + notificationRootsListChanged +
+			" notifications this session has sent. The client must declare the roots capability.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{
+			"timeout_ms": boundedProp("integer", "Milliseconds to wait for the client's answer", 1, maxRootsTimeout,
+				defaultRootsTimeout),
+		}),
+	}, tracker.listHandler)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListRoots_InputRequired lists roots over a 2026-07-28 session, where
+// the SDK's client answers the input request and calls the tool again,
+// and changes them in between.
+func TestListRoots_InputRequired(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addRootsTool(server)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+	client.AddRoots(&mcp.Root{URI: "file:///work/a", Name: "a"})
+	session, err := connectClient(ctx, t, server, client)
+	require.NoError(t, err)
+
+	listRoots := func() ListRootsResponse {
+		t.Helper()
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "list_roots", Arguments: map[string]any{}})
+		require.NoError(t, err)
+		require.False(t, result.IsError, "%v", result.Content)
+		var response ListRootsResponse
+		require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+		assert.Equal(t, roundTripInputRequired, response.RoundTrip)
+		return response
+	}
+
+	response := listRoots()
+	assert.Equal(t, []RootEntry{{URI: "file:///work/a", Name: "a"}}, response.Roots)
+	assert.Equal(t, 0, response.ListChanged)
+
+	client.AddRoots(&mcp.Root{URI: "file:///work/b"})
+	client.RemoveRoots("file:///work/a")
+	require.Eventually(t, func() bool { return listRoots().ListChanged == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []RootEntry{{URI: "file:///work/b"}}, listRoots().Roots)
+}
+
+// TestListRoots_Request lists roots over a 2025-11-25 session, which the
+// server sends roots/list.
+func TestListRoots_Request(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addRootsTool(server)
+
+	call := func(t *testing.T, c *legacyConn, args map[string]any) mcp.CallToolResult {
+		t.Helper()
+		resp, _ := c.call(ctx, "tools/call", map[string]any{"name": "list_roots", "arguments": args})
+		require.NoError(t, resp.Error)
+		var result mcp.CallToolResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		return result
+	}
+	rootsCapability := map[string]any{"roots": map[string]any{"listChanged": true}}
+
+	t.Run("answered", func(t *testing.T) {
+		c := newLegacyConnWithCapabilities(ctx, t, server, rootsCapability)
+		roots := []map[string]any{{"uri": "file:///work/a"}}
+		c.answer = func(req *jsonrpc.Request) any {
+			assert.Equal(t, "roots/list", req.Method)
+			return map[string]any{"roots": roots}
+		}
+		result := call(t, c, map[string]any{})
+		require.False(t, result.IsError, "%v", result.Content)
+		var response ListRootsResponse
+		require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+		assert.Equal(t, ListRootsResponse{
+			Roots: []RootEntry{{URI: "file:///work/a"}}, RoundTrip: roundTripRequest, ElapsedMS: response.ElapsedMS,
+		}, response)
+
+		roots = []map[string]any{{"uri": "file:///work/b", "name": "b"}, {"uri": "file:///work/c"}}
+		require.NoError(t, c.conn.Write(ctx, &jsonrpc.Request{
+			Method: notificationRootsListChanged, Params: json.RawMessage("{}"),
+		}))
+		result = call(t, c, map[string]any{})
+		require.False(t, result.IsError, "%v", result.Content)
+		require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+		assert.Equal(t, []RootEntry{{URI: "file:///work/b", Name: "b"}, {URI: "file:///work/c"}}, response.Roots)
+		assert.Equal(t, 1, response.ListChanged)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := newLegacyConnWithCapabilities(ctx, t, server, rootsCapability)
+		c.answer = func(*jsonrpc.Request) any { return nil }
+		result := call(t, c, map[string]any{"timeout_ms": 20})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "did not answer roots/list within 20ms")
+	})
+
+	t.Run("no capability", func(t *testing.T) {
+		c := newLegacyConn(ctx, t, server)
+		result := call(t, c, map[string]any{})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "client does not support roots")
+	})
+}
+
+// TestRootsTracker_ClosedSessions checks the count of a session that has
+// ended is dropped when another session's notification arrives.
+func TestRootsTracker_ClosedSessions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	tracker := &rootsTracker{server: server, changes: map[mcp.Session]int{}}
+	server.AddReceivingMiddleware(tracker.middleware)
+	rootsCapability := map[string]any{"roots": map[string]any{"listChanged": true}}
+	counted := func() int {
+		tracker.mu.Lock()
+		defer tracker.mu.Unlock()
+		total := 0
+		for _, n := range tracker.changes {
+			total += n
+		}
+		return total
+	}
+	changed := &jsonrpc.Request{Method: notificationRootsListChanged, Params: json.RawMessage("{}")}
+
+	first := newLegacyConnWithCapabilities(ctx, t, server, rootsCapability)
+	require.NoError(t, first.conn.Write(ctx, changed))
+	require.Eventually(t, func() bool { return counted() == 1 }, time.Second, 10*time.Millisecond)
+	require.NoError(t, first.conn.Close())
+	require.Eventually(t, func() bool { return len(connectedSessions(server)) == 0 }, time.Second, 10*time.Millisecond)
+
+	second := newLegacyConnWithCapabilities(ctx, t, server, rootsCapability)
+	require.NoError(t, second.conn.Write(ctx, changed))
+	require.Eventually(t, func() bool {
+		for session := range connectedSessions(server) {
+			tracker.mu.Lock()
+			defer tracker.mu.Unlock()
+			return tracker.changes[session] == 1
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, counted())
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultSampleMaxTokens = 256
+	maxSampleMaxTokens     = 100000
+	defaultSampleTimeout   = 30000
+	maxSampleTimeout       = 600000
+
+	// sampleInputID is the ID of the sampling request in a 2026-07-28
+	// input-required result.
+	sampleInputID = "sample"
+
+	roundTripRequest       = "request"
+	roundTripInputRequired = "input_required"
+)
+
+// SampleRequest represents the request for the sample tool
+type SampleRequest struct {
+	Prompt       string `json:"prompt"`
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	MaxTokens    int    `json:"max_tokens,omitempty"`
+	TimeoutMS    int    `json:"timeout_ms,omitempty"`
+}
+
+// SampleResponse represents the response from the sample tool: the
+// client's answer to the sampling request.
+type SampleResponse struct {
+	Model      string `json:"model"`
+	Role       string `json:"role"`
+	StopReason string `json:"stop_reason,omitempty"`
+	Text       string `json:"text"`
+	// RoundTrip is how the request reached the client: request, as a
+	// sampling/createMessage request of the server's own, or
+	// input_required, as an input request in the tool's result, which the
+	// client answered by calling the tool again.
+	RoundTrip string `json:"round_trip"`
+	// ElapsedMS is how long the client took to answer.
+	ElapsedMS int64 `json:"elapsed_ms"`
+}
+
+// samplingParams builds the request sent to the client: a single user
+// message holding the prompt.
+func samplingParams(params SampleRequest) *mcp.CreateMessageParams {
+	return &mcp.CreateMessageParams{
+		Messages:     []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: params.Prompt}}},
+		SystemPrompt: params.SystemPrompt,
+		MaxTokens:    int64(params.MaxTokens),
+	}
+}
+
+// sampleResponse turns the client's answer into the tool's response. Only
+// text is expected back; anything else is reported rather than dropped.
+func sampleResponse(
+	result *mcp.CreateMessageWithToolsResult, roundTrip string, elapsed time.Duration,
+) (SampleResponse, error) {
+	var text strings.Builder
+	for _, content := range result.Content {
+		c, ok := content.(*mcp.TextContent)
+		if !ok {
+			return SampleResponse{}, fmt.Errorf("client answered with %T content; only text is supported", content)
+		}
+		text.WriteString(c.Text)
+	}
+	return SampleResponse{
+		Model:      result.Model,
+		Role:       string(result.Role),
+		StopReason: result.StopReason,
+		Text:       text.String(),
+		RoundTrip:  roundTrip,
+		ElapsedMS:  elapsed.Milliseconds(),
+	}, nil
+}
+
+// withDefaults fills in the defaults of a sample request and checks its
+// bounds.
+func (params SampleRequest) withDefaults() (SampleRequest, error) {
+	if params.Prompt == "" {
+		return params, errors.New("prompt is required")
+	}
+	if params.MaxTokens == 0 {
+		params.MaxTokens = defaultSampleMaxTokens
+	}
+	if params.MaxTokens < 1 || params.MaxTokens > maxSampleMaxTokens {
+		return params, fmt.Errorf("max_tokens must be between 1 and %d (got %d)", maxSampleMaxTokens, params.MaxTokens)
+	}
+	if params.TimeoutMS == 0 {
+		params.TimeoutMS = defaultSampleTimeout
+	}
+	if params.TimeoutMS < 1 || params.TimeoutMS > maxSampleTimeout {
+		return params, fmt.Errorf("timeout_ms must be between 1 and %d (got %d)", maxSampleTimeout, params.TimeoutMS)
+	}
+	return params, nil
+}
+
+// sampleHandler asks the client to sample a message: with an input
+// request if the client uses them, otherwise with sampling/createMessage.
+func sampleHandler(
+	ctx context.Context, req *mcp.CallToolRequest, params SampleRequest,
+) (*mcp.CallToolResult, SampleResponse, error) {
+	params, err := params.withDefaults()
+	if err != nil {
+		return nil, SampleResponse{}, err
+	}
+	if caps := req.ClientCapabilities(); caps == nil || caps.Sampling == nil {
+		return nil, SampleResponse{}, errors.New("client does not support sampling: it did not declare the sampling capability")
+	}
+	if !usesInputRequests(req) {
+		response, err := sampleByRequest(ctx, req.Session, params)
+		return nil, response, err
+	}
+	return sampleByInputRequest(req, params)
+}
+
+// usesInputRequests reports whether req's client is on the 2026-07-28
+// protocol, which has servers ask their clients for input with input
+// requests in results rather than requests of their own.
+func usesInputRequests(req *mcp.CallToolRequest) bool {
+	init := req.Session.InitializeParams()
+	return init != nil && init.ProtocolVersion >= protocolVersionModern
+}
+
+// inputRequestState is the request state of an input-required result: the
+// time it was issued, so inputElapsed can tell how long the client took to
+// answer. The client could change it, but a test server has nothing to
+// protect.
+func inputRequestState() string {
+	return strconv.FormatInt(time.Now().UnixMilli(), 10)
+}
+
+// inputElapsed returns how long the client took to answer the input
+// request req answers. The server can't cancel a request it didn't send,
+// so an answer later than timeoutMS is rejected instead.
+func inputElapsed(req *mcp.CallToolRequest, timeoutMS int) (time.Duration, error) {
+	issued, err := strconv.ParseInt(req.Params.RequestState, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid request state %q", req.Params.RequestState)
+	}
+	elapsed := time.Since(time.UnixMilli(issued))
+	if elapsed > time.Duration(timeoutMS)*time.Millisecond {
+		return 0, fmt.Errorf("client answered the input request after %dms, later than timeout_ms (%d)",
+			elapsed.Milliseconds(), timeoutMS)
+	}
+	return elapsed, nil
+}
+
+// sampleByRequest sends sampling/createMessage and waits at most
+// timeout_ms for the answer, cancelling the request if it doesn't come.
+func sampleByRequest(ctx context.Context, session *mcp.ServerSession, params SampleRequest) (SampleResponse, error) {
+	sampleCtx, cancel := context.WithTimeout(ctx, time.Duration(params.TimeoutMS)*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	result, err := session.CreateMessage(sampleCtx, samplingParams(params))
+	elapsed := time.Since(start)
+	switch {
+	case err != nil && ctx.Err() == nil && sampleCtx.Err() != nil:
+		return SampleResponse{}, fmt.Errorf("client did not answer sampling/createMessage within %dms", params.TimeoutMS)
+	case err != nil:
+		return SampleResponse{}, fmt.Errorf("sampling/createMessage failed: %w", err)
+	}
+	var content []mcp.Content
+	if result.Content != nil {
+		content = []mcp.Content{result.Content}
+	}
+	return sampleResponse(&mcp.CreateMessageWithToolsResult{
+		Content: content, Model: result.Model, Role: result.Role, StopReason: result.StopReason,
+	}, roundTripRequest, elapsed)
+}
+
+// sampleByInputRequest returns the sampling request as an input request.
+// The client answers by calling the tool again with the answer.
+func sampleByInputRequest(req *mcp.CallToolRequest, params SampleRequest) (*mcp.CallToolResult, SampleResponse, error) {
+	answer, ok := req.Params.InputResponses[sampleInputID]
+	if !ok {
+		return &mcp.CallToolResult{
+			InputRequests: mcp.InputRequestMap{sampleInputID: samplingParams(params)},
+			RequestState:  inputRequestState(),
+		}, SampleResponse{}, nil
+	}
+	result, ok := answer.(*mcp.CreateMessageWithToolsResult)
+	if !ok {
+		return nil, SampleResponse{}, fmt.Errorf("input response %q is %T, not a sampling result", sampleInputID, answer)
+	}
+	elapsed, err := inputElapsed(req, params.TimeoutMS)
+	if err != nil {
+		return nil, SampleResponse{}, err
+	}
+	response, err := sampleResponse(result, roundTripInputRequired, elapsed)
+	return nil, response, err
+}
+
+// addSamplingTool registers sample, which asks the client to sample a
+// message and returns its answer.
+func addSamplingTool(server *mcp.Server) {
+	addTool(server, &mcp.Tool{
+		Name: "sample",
+		Description: "Ask the client to sample an LLM message for prompt and return its answer. The client must " +
+			"declare the sampling capability. Clients on protocol 2026-07-28 get the request as an input request " +
+			"and answer it by calling the tool again; older clients are sent sampling/createMessage.",
+		InputSchema: objectSchema([]string{"prompt"}, map[string]*jsonschema.Schema{
+			"prompt":        {Type: "string", Description: "Text of the user message to sample"},
+			"system_prompt": {Type: "string", Description: "System prompt to send with it"},
+			"max_tokens": boundedProp("integer", "Most tokens the client may sample", 1, maxSampleMaxTokens,
+				defaultSampleMaxTokens),
+			"timeout_ms": boundedProp("integer", "Milliseconds to wait for the client's answer", 1, maxSampleTimeout,
+				defaultSampleTimeout),
+		}),
+	}, sampleHandler)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampleRequest_WithDefaults(t *testing.T) {
+	got, err := SampleRequest{Prompt: "hi"}.withDefaults()
+	require.NoError(t, err)
+	assert.Equal(t, SampleRequest{Prompt: "hi", MaxTokens: defaultSampleMaxTokens, TimeoutMS: defaultSampleTimeout}, got)
+
+	tests := []struct {
+		req     SampleRequest
+		wantErr string
+	}{
+		{SampleRequest{}, "prompt is required"},
+		{SampleRequest{Prompt: "hi", MaxTokens: -1}, "max_tokens must be between 1 and"},
+		{SampleRequest{Prompt: "hi", MaxTokens: maxSampleMaxTokens + 1}, "max_tokens must be between 1 and"},
+		{SampleRequest{Prompt: "hi", TimeoutMS: maxSampleTimeout + 1}, "timeout_ms must be between 1 and"},
+	}
+	for _, tt := range tests {
+		_, err := tt.req.withDefaults()
+		assert.ErrorContains(t, err, tt.wantErr, "%+v", tt.req)
+	}
+}
+
+// reverseSampler answers sampling requests with the reversed text of the
+// last message, after delay.
+func reverseSampler(delay time.Duration) func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		text := req.Params.Messages[len(req.Params.Messages)-1].Content.(*mcp.TextContent).Text
+		runes := []rune(text)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return &mcp.CreateMessageResult{
+			Model: "reverse", Role: "assistant", StopReason: "endTurn", Content: &mcp.TextContent{Text: string(runes)},
+		}, nil
+	}
+}
+
+func connectSampling(ctx context.Context, t *testing.T, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	return connectInMemory(ctx, t, nil, addSamplingTool, opts)
+}
+
+// TestSample_InputRequired samples over a 2026-07-28 session, where the
+// SDK's client answers the input request and calls the tool again.
+func TestSample_InputRequired(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session := connectSampling(ctx, t, &mcp.ClientOptions{CreateMessageHandler: reverseSampler(0)})
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "sample", Arguments: map[string]any{"prompt": "héllo"}})
+	require.NoError(t, err)
+	require.False(t, result.IsError, "%v", result.Content)
+	var response SampleResponse
+	require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+	assert.Equal(t, "olléh", response.Text)
+	assert.Equal(t, "reverse", response.Model)
+	assert.Equal(t, "assistant", response.Role)
+	assert.Equal(t, "endTurn", response.StopReason)
+	assert.Equal(t, roundTripInputRequired, response.RoundTrip)
+
+	session = connectSampling(ctx, t, &mcp.ClientOptions{CreateMessageHandler: reverseSampler(100 * time.Millisecond)})
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "sample", Arguments: map[string]any{
+		"prompt": "hello", "timeout_ms": 20,
+	}})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "later than timeout_ms (20)")
+
+	session = connectSampling(ctx, t, nil)
+	result, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "sample", Arguments: map[string]any{"prompt": "hello"}})
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "client does not support sampling")
+}
+
+// TestSample_Request samples over a 2025-11-25 session, which the server
+// sends sampling/createMessage.
+func TestSample_Request(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addSamplingTool(server)
+
+	call := func(t *testing.T, c *legacyConn, args map[string]any) mcp.CallToolResult {
+		t.Helper()
+		resp, _ := c.call(ctx, "tools/call", map[string]any{"name": "sample", "arguments": args})
+		require.NoError(t, resp.Error)
+		var result mcp.CallToolResult
+		require.NoError(t, json.Unmarshal(resp.Result, &result))
+		return result
+	}
+
+	t.Run("answered", func(t *testing.T) {
+		c := newLegacyConnWithCapabilities(ctx, t, server, map[string]any{"sampling": map[string]any{}})
+		var params mcp.CreateMessageParams
+		c.answer = func(req *jsonrpc.Request) any {
+			assert.Equal(t, "sampling/createMessage", req.Method)
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+			return map[string]any{
+				"model": "upper", "role": "assistant",
+				"content": map[string]any{"type": "text", "text": strings.ToUpper(params.Messages[0].Content.(*mcp.TextContent).Text)},
+			}
+		}
+		result := call(t, c, map[string]any{"prompt": "hello", "system_prompt": "be loud", "max_tokens": 5})
+		require.False(t, result.IsError, "%v", result.Content)
+		assert.Equal(t, "be loud", params.SystemPrompt)
+		assert.Equal(t, int64(5), params.MaxTokens)
+		var response SampleResponse
+		require.NoError(t, json.Unmarshal([]byte(mustJSON(t, result.StructuredContent)), &response))
+		assert.Equal(t, SampleResponse{
+			Model: "upper", Role: "assistant", Text: "HELLO", RoundTrip: roundTripRequest, ElapsedMS: response.ElapsedMS,
+		}, response)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := newLegacyConnWithCapabilities(ctx, t, server, map[string]any{"sampling": map[string]any{}})
+		c.answer = func(*jsonrpc.Request) any { return nil }
+		result := call(t, c, map[string]any{"prompt": "hello", "timeout_ms": 20})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "did not answer sampling/createMessage within 20ms")
+	})
+
+	t.Run("no capability", func(t *testing.T) {
+		c := newLegacyConn(ctx, t, server)
+		result := call(t, c, map[string]any{"prompt": "hello"})
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "client does not support sampling")
+	})
+}
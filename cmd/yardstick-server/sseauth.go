@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+)
+
+// sseSessionParam is the query parameter of the SDK's SSE session endpoint.
+const sseSessionParam = "sessionid"
+
+// sseCredentials remembers the query credential that authenticated each
+// open SSE stream, by session ID. The SDK's session endpoint replaces the
+// GET's query string with ?sessionid=..., so a query credential is gone
+// from every POST; the session ID, which can't be guessed, stands in for
+// it. Other credentials survive the endpoint, so POSTs must still carry
+// them.
+type sseCredentials struct {
+	mu       sync.Mutex
+	sessions map[string]*credential
+}
+
+func newSSECredentials() *sseCredentials {
+	return &sseCredentials{sessions: map[string]*credential{}}
+}
+
+// wrap is authWrapper for the SSE endpoint: a GET is authenticated as
+// usual and a query credential remembered until the stream ends, and a
+// POST to a remembered session is accepted with that session's credential.
+func (s *sseCredentials) wrap(next http.Handler) http.Handler {
+	auth := authWrapper(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			c, err := matchCredential(r)
+			if err != nil || c == nil || c.Type != credentialTypeQuery {
+				auth.ServeHTTP(w, r)
+				return
+			}
+			sw := &sseEndpointWriter{ResponseWriter: w, record: func(id string) { s.set(id, c) }}
+			defer func() { s.remove(sw.sessionID) }()
+			next.ServeHTTP(sw, r.WithContext(withCredential(r.Context(), c)))
+		case http.MethodPost:
+			if c := s.get(r.URL.Query().Get(sseSessionParam)); c != nil {
+				next.ServeHTTP(w, r.WithContext(withCredential(r.Context(), c)))
+				return
+			}
+			auth.ServeHTTP(w, r)
+		default:
+			auth.ServeHTTP(w, r)
+		}
+	})
+}
+
+func (s *sseCredentials) set(id string, c *credential) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = c
+}
+
+func (s *sseCredentials) get(id string) *credential {
+	if id == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[id]
+}
+
+func (s *sseCredentials) remove(id string) {
+	if id == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+// sseEndpointWriter reads the session ID from an SSE stream's endpoint
+// event, the first thing the SDK writes, and passes it to record.
+type sseEndpointWriter struct {
+	http.ResponseWriter
+	record    func(id string)
+	written   bool
+	sessionID string
+}
+
+func (w *sseEndpointWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		if _, rest, ok := bytes.Cut(p, []byte(sseSessionParam+"=")); ok {
+			if end := bytes.IndexAny(rest, "&\r\n"); end >= 0 {
+				rest = rest[:end]
+			}
+			w.sessionID = string(rest)
+			w.record(w.sessionID)
+		}
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *sseEndpointWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *sseEndpointWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
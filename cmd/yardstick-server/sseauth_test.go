@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSSECredentials_Query connects over SSE with a query credential, which
+// the session endpoint drops, and checks the POSTs are still accepted with
+// the credential that opened the stream.
+func TestSSECredentials_Query(t *testing.T) {
+	withCredentials(t, "", "", []credential{
+		{Name: "tenant-a", Type: credentialTypeQuery, Param: "api_key", Value: "key-a"},
+		{Name: "tenant-b", Type: credentialTypeQuery, Param: "api_key", Value: "key-b"},
+	})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, whoamiHandler)
+	credentials := newSSECredentials()
+	handler := mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server { return server }, nil)
+	ts := httptest.NewServer(credentials.wrap(handler))
+	defer ts.Close()
+
+	for _, tenant := range []struct{ key, name string }{{"key-a", "tenant-a"}, {"key-b", "tenant-b"}} {
+		t.Run(tenant.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, &mcp.SSEClientTransport{Endpoint: ts.URL + "?api_key=" + tenant.key}, nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami"})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"authenticated": true, "credential": tenant.name, "type": "query"},
+				result.StructuredContent)
+		})
+	}
+
+	// Closed streams are forgotten, so their session IDs stop working.
+	require.Eventually(t, func() bool {
+		credentials.mu.Lock()
+		defer credentials.mu.Unlock()
+		return len(credentials.sessions) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	tests := []struct {
+		name, method, target string
+	}{
+		{"GET without credential", http.MethodGet, ts.URL},
+		{"GET with wrong credential", http.MethodGet, ts.URL + "?api_key=nope"},
+		{"POST to unknown session", http.MethodPost, ts.URL + "?sessionid=unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.target, strings.NewReader("{}"))
+			require.NoError(t, err)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		})
+	}
+}
+
+// TestSSECredentials_Header checks POSTs to a stream opened with a header
+// credential must still carry it, as the session endpoint doesn't drop it.
+func TestSSECredentials_Header(t *testing.T) {
+	withCredentials(t, "", "", []credential{
+		{Name: "tenant-a", Type: credentialTypeHeader, Header: "X-API-Key", Value: "key-a"},
+	})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	credentials := newSSECredentials()
+	handler := mcp.NewSSEHandler(func(_ *http.Request) *mcp.Server { return server }, nil)
+	ts := httptest.NewServer(credentials.wrap(handler))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Key", "key-a")
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	var endpoint string
+	scanner := bufio.NewScanner(stream.Body)
+	for endpoint == "" && scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			endpoint = data
+		}
+	}
+	require.Contains(t, endpoint, sseSessionParam+"=")
+	credentials.mu.Lock()
+	assert.Empty(t, credentials.sessions)
+	credentials.mu.Unlock()
+
+	post := func(key string) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+endpoint,
+			strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusUnauthorized, post(""))
+	assert.Equal(t, http.StatusUnauthorized, post("wrong"))
+	assert.Equal(t, http.StatusAccepted, post("key-a"))
+}
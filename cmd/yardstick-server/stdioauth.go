@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// stdioAuth authenticates stdio sessions, which have no HTTP request for
+// authWrapper to check. A launcher can present a credential either in the
+// initialize request's _meta under metaKey, or in the server's environment
+// under envVar; either one matching an accepted credential (see
+// acceptedCredentials) authenticates the session.
+type stdioAuth struct {
+	metaKey string
+	envVar  string
+
+	mu       sync.Mutex
+	sessions map[mcp.Session]*credential
+}
+
+// enabled reports whether any stdio credential source is configured.
+func (a *stdioAuth) enabled() bool {
+	return a.metaKey != "" || a.envVar != ""
+}
+
+// presented returns the accepted credential that req (or the environment)
+// presents, or nil if there is none.
+func (a *stdioAuth) presented(req mcp.Request) *credential {
+	if a.metaKey != "" {
+		if v, ok := requestMeta(req)[a.metaKey].(string); ok {
+			if c := matchCredentialValue(v); c != nil {
+				return c
+			}
+		}
+	}
+	if a.envVar != "" {
+		if v, ok := os.LookupEnv(a.envVar); ok {
+			return matchCredentialValue(v)
+		}
+	}
+	return nil
+}
+
+// middleware rejects every request on a stdio session until it presents an
+// accepted credential, failing the handshake if it doesn't carry one. The
+// handshake is initialize for Legacy clients and server/discover for Modern
+// ones (which fall back to initialize when discover fails, so both must be
+// guarded). The credential presented on the handshake is remembered for the
+// rest of the session and attached to each request's context, so whoami
+// reports it just like it does for the HTTP transports; a session that
+// skipped the handshake is checked request by request.
+//
+// Other lifecycle traffic (see isLifecycleMethod) passes through, so a
+// client can still ping the server before authenticating.
+func (a *stdioAuth) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if !isStdioSession(ctx) {
+			// HTTP sessions sharing the server (see -attach-stdio) are
+			// authenticated by authWrapper instead.
+			return next(ctx, method, req)
+		}
+		session := req.GetSession()
+
+		a.mu.Lock()
+		c := a.sessions[session]
+		a.mu.Unlock()
+
+		if c == nil {
+			handshake := method == methodInitialize || method == methodDiscover
+			c = a.presented(req)
+			if c == nil {
+				if !handshake && isLifecycleMethod(method) {
+					return next(ctx, method, req)
+				}
+				log.Printf("stdio auth: rejecting %q: no accepted credential presented", method)
+				return nil, errors.New("unauthorized")
+			}
+			if handshake {
+				a.mu.Lock()
+				a.sessions[session] = c
+				a.mu.Unlock()
+			}
+		}
+		return next(withCredential(ctx, c), method, req)
+	}
+}
+
+// requestMeta returns the _meta of req's params, tolerating requests whose
+// params are absent: the SDK hands middleware a typed nil pointer for
+// those, and calling GetMeta on it would panic.
+func requestMeta(req mcp.Request) map[string]any {
+	p := req.GetParams()
+	if p == nil || reflect.ValueOf(p).IsNil() {
+		return nil
+	}
+	return p.GetMeta()
+}
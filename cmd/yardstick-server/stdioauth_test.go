@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testStdioAuthMetaKey = "yardstick/credential"
+
+// connectStdioAuth connects an in-memory client to a server guarded by sa,
+// adding meta to the handshake (server/discover, then initialize if the
+// client falls back to it), as a launcher would over stdio.
+func connectStdioAuth(t *testing.T, sa *stdioAuth, meta mcp.Meta) (*mcp.ClientSession, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, whoamiHandler)
+	server.AddReceivingMiddleware(sa.middleware)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+	client.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if (method == methodInitialize || method == methodDiscover) && meta != nil {
+				merged := mcp.Meta{}
+				maps.Copy(merged, req.GetParams().GetMeta())
+				maps.Copy(merged, meta)
+				req.GetParams().SetMeta(merged)
+			}
+			return next(ctx, method, req)
+		}
+	})
+	return connectClient(context.WithValue(ctx, stdioSessionKey{}, true), t, server, client)
+}
+
+func TestStdioAuth_Meta(t *testing.T) {
+	withCredentials(t, "", "", []credential{
+		{Name: "tenant-a", Type: credentialTypeBearer, Value: "key-a"},
+		{Name: "tenant-b", Type: credentialTypeBasic, Username: "bob", Password: "pw"},
+	})
+
+	tests := []struct {
+		name     string
+		meta     mcp.Meta
+		expected string
+		wantErr  bool
+	}{
+		{name: "missing credential fails handshake", wantErr: true},
+		{name: "wrong credential fails handshake", meta: mcp.Meta{testStdioAuthMetaKey: "key-z"}, wantErr: true},
+		{name: "wrong type fails handshake", meta: mcp.Meta{testStdioAuthMetaKey: 42}, wantErr: true},
+		{name: "bearer value", meta: mcp.Meta{testStdioAuthMetaKey: "key-a"}, expected: "tenant-a"},
+		{name: "basic value", meta: mcp.Meta{testStdioAuthMetaKey: "bob:pw"}, expected: "tenant-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := &stdioAuth{metaKey: testStdioAuthMetaKey, sessions: map[mcp.Session]*credential{}}
+			session, err := connectStdioAuth(t, sa, tt.meta)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// The credential presented on initialize must be remembered for
+			// later calls, which carry no _meta of their own.
+			result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.StructuredContent.(map[string]any)["credential"])
+		})
+	}
+}
+
+func TestStdioAuth_Env(t *testing.T) {
+	withCredentials(t, "X-Auth-Token", "secret123", nil)
+	sa := &stdioAuth{envVar: "YARDSTICK_TEST_CREDENTIAL", sessions: map[mcp.Session]*credential{}}
+
+	t.Run("unset env fails handshake", func(t *testing.T) {
+		_, err := connectStdioAuth(t, sa, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("matching env authenticates", func(t *testing.T) {
+		t.Setenv("YARDSTICK_TEST_CREDENTIAL", "secret123")
+		session, err := connectStdioAuth(t, sa, nil)
+		require.NoError(t, err)
+
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+		require.NoError(t, err)
+		assert.Equal(t, credentialNameDefault, result.StructuredContent.(map[string]any)["credential"])
+	})
+}
+
+func TestStdioAuth_LifecycleBypass(t *testing.T) {
+	withCredentials(t, "X-Auth-Token", "secret123", nil)
+	sa := &stdioAuth{metaKey: testStdioAuthMetaKey, sessions: map[mcp.Session]*credential{}}
+	handler := sa.middleware(noopHandler)
+	ctx := context.WithValue(context.Background(), stdioSessionKey{}, true)
+
+	_, err := handler(ctx, methodPing, &mcp.ServerRequest[*mcp.PingParams]{})
+	assert.NoError(t, err)
+	_, err = handler(ctx, "tools/list", &mcp.ServerRequest[*mcp.ListToolsParams]{})
+	assert.Error(t, err)
+	_, err = handler(ctx, "tools/list", &mcp.ServerRequest[*mcp.ListToolsParams]{
+		Params: &mcp.ListToolsParams{Meta: mcp.Meta{testStdioAuthMetaKey: "secret123"}},
+	})
+	assert.NoError(t, err)
+}
+
+func TestStdioAuth_IgnoresHTTPSessions(t *testing.T) {
+	withCredentials(t, "X-Auth-Token", "secret123", nil)
+	sa := &stdioAuth{metaKey: testStdioAuthMetaKey, sessions: map[mcp.Session]*credential{}}
+	handler := sa.middleware(noopHandler)
+
+	// Without the stdio mark the request came from an HTTP session on a
+	// shared server, which authWrapper has already checked.
+	_, err := handler(context.Background(), "tools/list", &mcp.ServerRequest[*mcp.ListToolsParams]{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	reportStatusOK       = "ok"
+	reportStatusDegraded = "degraded"
+	reportStatusFailed   = "failed"
+
+	// Ways structured_violation can break reportOutputSchema.
+	violationWrongType       = "wrong_type"
+	violationMissingRequired = "missing_required"
+	violationBadEnum         = "bad_enum"
+	violationExtraProperty   = "extra_property"
+	violationNullNotAllowed  = "null_not_allowed"
+	violationNestedRange     = "nested_range"
+
+	defaultReportItems = 3
+	maxReportItems     = 100
+)
+
+var reportStatuses = []string{reportStatusOK, reportStatusDegraded, reportStatusFailed}
+
+var schemaViolations = []string{
+	violationWrongType, violationMissingRequired, violationBadEnum,
+	violationExtraProperty, violationNullNotAllowed, violationNestedRange,
+}
+
+// ReportRequest represents the request for the structured_report and
+// structured_violation tools
+type ReportRequest struct {
+	Subject   string  `json:"subject"`
+	Items     int     `json:"items,omitempty"`
+	Status    string  `json:"status,omitempty"`
+	Owner     *string `json:"owner,omitempty"`
+	Violation string  `json:"violation,omitempty"`
+}
+
+// ReportMetrics is the nested metrics object of a ReportResponse
+type ReportMetrics struct {
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+	Ratio float64 `json:"ratio"`
+}
+
+// ReportItem is one entry of a ReportResponse's items array
+type ReportItem struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// ReportResponse represents the response from the structured_report tool
+type ReportResponse struct {
+	Subject  string        `json:"subject"`
+	Sequence int           `json:"sequence"`
+	Status   string        `json:"status"`
+	Metrics  ReportMetrics `json:"metrics"`
+	Items    []ReportItem  `json:"items"`
+	Tags     []string      `json:"tags"`
+	Owner    *string       `json:"owner"`
+}
+
+func closedObject(required []string, props map[string]*jsonschema.Schema) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           props,
+		Required:             required,
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+}
+
+// reportOutputSchema is declared by hand rather than inferred from
+// ReportResponse, so that it exercises everything a client validator has
+// to get right: nested closed objects, arrays of objects, an enum, numeric
+// bounds and a nullable field.
+func reportOutputSchema() *jsonschema.Schema {
+	zero, one := 0.0, 1.0
+	return closedObject(
+		[]string{"subject", "sequence", "status", "metrics", "items", "tags", "owner"},
+		map[string]*jsonschema.Schema{
+			"subject": {Type: "string", Description: "The subject the report is about"},
+			"sequence": {Type: "integer", Minimum: &one,
+				Description: "How many reports this server has produced for the subject"},
+			"status": {Type: "string", Enum: []any{reportStatusOK, reportStatusDegraded, reportStatusFailed}},
+			"metrics": closedObject([]string{"count", "total", "ratio"}, map[string]*jsonschema.Schema{
+				"count": {Type: "integer", Minimum: &zero},
+				"total": {Type: "number"},
+				"ratio": {Type: "number", Minimum: &zero, Maximum: &one},
+			}),
+			"items": {Type: "array", Items: closedObject([]string{"name", "value"}, map[string]*jsonschema.Schema{
+				"name":  {Type: "string"},
+				"value": {Type: "number"},
+			})},
+			"tags":  {Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+			"owner": {Types: []string{"string", "null"}, Description: "The report's owner, or null if unowned"},
+		},
+	)
+}
+
+func reportInputSchema(withViolation bool) *jsonschema.Schema {
+	one, limit := 1.0, float64(maxReportItems)
+	props := map[string]*jsonschema.Schema{
+		"subject": {Type: "string", Description: "What the report is about"},
+		"items": {Type: "integer", Minimum: &one, Maximum: &limit,
+			Description: fmt.Sprintf("Number of items to include (default %d)", defaultReportItems)},
+		"status": {Type: "string", Enum: []any{reportStatusOK, reportStatusDegraded, reportStatusFailed},
+			Description: "Status to report (default ok)"},
+		"owner": {Types: []string{"string", "null"}, Description: "Owner to report; omit or null for none"},
+	}
+	if withViolation {
+		enum := make([]any, len(schemaViolations))
+		for i, v := range schemaViolations {
+			enum[i] = v
+		}
+		props["violation"] = &jsonschema.Schema{Type: "string", Enum: enum,
+			Description: fmt.Sprintf("How to violate the output schema (default %s)", violationWrongType)}
+	}
+	return &jsonschema.Schema{Type: "object", Properties: props, Required: []string{"subject"}}
+}
+
+// reportState numbers the reports produced for each subject, making
+// structured_report stateful: repeating a call yields the same report with
+// the next sequence number, so a proxy that caches or replays
+// structuredContent is caught out.
+type reportState struct {
+	mu        sync.Mutex
+	sequences map[string]int
+}
+
+func (s *reportState) next(subject string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequences[subject]++
+	return s.sequences[subject]
+}
+
+// buildReport deterministically derives a report from params. Everything
+// but the sequence number depends only on the request.
+func (s *reportState) buildReport(params ReportRequest) (ReportResponse, error) {
+	if params.Subject == "" {
+		return ReportResponse{}, fmt.Errorf("subject is required")
+	}
+	n := params.Items
+	if n == 0 {
+		n = defaultReportItems
+	}
+	if n < 1 || n > maxReportItems {
+		return ReportResponse{}, fmt.Errorf("items must be between 1 and %d (got %d)", maxReportItems, params.Items)
+	}
+	status := params.Status
+	if status == "" {
+		status = reportStatusOK
+	}
+	if !slices.Contains(reportStatuses, status) {
+		return ReportResponse{}, fmt.Errorf("status must be one of %v (got %q)", reportStatuses, status)
+	}
+
+	report := ReportResponse{
+		Subject:  params.Subject,
+		Sequence: s.next(params.Subject),
+		Status:   status,
+		Items:    make([]ReportItem, n),
+		Tags:     []string{"yardstick", "status:" + status},
+		Owner:    params.Owner,
+	}
+	for i := range report.Items {
+		value := float64(i+1) * 1.5
+		report.Items[i] = ReportItem{Name: fmt.Sprintf("%s-%d", params.Subject, i+1), Value: value}
+		report.Metrics.Total += value
+	}
+	report.Metrics.Count = n
+	report.Metrics.Ratio = float64(n) / float64(maxReportItems)
+	return report, nil
+}
+
+func (s *reportState) reportHandler(
+	_ context.Context, _ *mcp.CallToolRequest, params ReportRequest,
+) (*mcp.CallToolResult, ReportResponse, error) {
+	// An error is reported as a tool error; returning it alongside a zero
+	// ReportResponse would fail the SDK's output validation instead.
+	report, err := s.buildReport(params)
+	if err != nil {
+		return nil, ReportResponse{}, err
+	}
+	return nil, report, nil
+}
+
+// violationHandler returns a report broken in the way the violation
+// argument asks for. It is a raw handler because mcp.AddTool validates
+// typed output against the schema and would refuse to send it.
+func (s *reportState) violationHandler(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var params ReportRequest
+	if len(req.Params.Arguments) > 0 {
+		if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	violation := params.Violation
+	if violation == "" {
+		violation = violationWrongType
+	}
+	if !slices.Contains(schemaViolations, violation) {
+		return errorResult(fmt.Sprintf("violation must be one of %v (got %q)", schemaViolations, violation)), nil
+	}
+	report, err := s.buildReport(params)
+	if err != nil {
+		return errorResult(err.Error()), nil
+	}
+
+	out, err := violateReport(report, violation)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(data)}},
+		StructuredContent: out,
+	}, nil
+}
+
+// violateReport converts report to its JSON object form and breaks it.
+func violateReport(report ReportResponse, violation string) (map[string]any, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	switch violation {
+	case violationWrongType:
+		out["sequence"] = fmt.Sprint(report.Sequence)
+	case violationMissingRequired:
+		delete(out, "status")
+	case violationBadEnum:
+		out["status"] = "unknown"
+	case violationExtraProperty:
+		out["unexpected"] = true
+	case violationNullNotAllowed:
+		out["subject"] = nil
+	case violationNestedRange:
+		out["metrics"].(map[string]any)["ratio"] = 1.5
+	}
+	return out, nil
+}
+
+func errorResult(text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+		IsError: true,
+	}
+}
+
+// addStructuredTools registers the structured-output tools, which share
+// reportOutputSchema: structured_report always conforms to it, and
+// structured_violation never does.
+func addStructuredTools(server *mcp.Server) {
+	state := &reportState{sequences: map[string]int{}}
+
+	addTool(server, &mcp.Tool{
+		Name: "structured_report",
+		Description: "Return a deterministic report as structured content conforming to the declared output schema " +
+			"(nested objects, arrays, an enum, numeric bounds and a nullable field). " +
+			"The sequence number counts the reports produced for each subject.",
+		InputSchema:  reportInputSchema(false),
+		OutputSchema: reportOutputSchema(),
+	}, state.reportHandler)
+
+	server.AddTool(&mcp.Tool{
+		Name: "structured_violation",
+		Description: "Return the same report as structured_report, deliberately broken so it violates the declared " +
+			"output schema, for testing client-side output validation.",
+		InputSchema:  reportInputSchema(true),
+		OutputSchema: reportOutputSchema(),
+	}, state.violationHandler)
+}
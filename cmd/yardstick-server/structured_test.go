@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validateReport validates v, as it would arrive on the wire, against
+// reportOutputSchema.
+func validateReport(t *testing.T, v any) error {
+	t.Helper()
+	resolved, err := reportOutputSchema().Resolve(nil)
+	require.NoError(t, err)
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	var instance any
+	require.NoError(t, json.Unmarshal(data, &instance))
+	return resolved.Validate(instance)
+}
+
+func TestBuildReport(t *testing.T) {
+	owner := "alice"
+	tests := []struct {
+		name    string
+		params  ReportRequest
+		wantErr string
+	}{
+		{name: "defaults", params: ReportRequest{Subject: "disk"}},
+		{name: "all fields", params: ReportRequest{Subject: "disk", Items: 5, Status: reportStatusDegraded, Owner: &owner}},
+		{name: "max items", params: ReportRequest{Subject: "disk", Items: maxReportItems}},
+		{name: "missing subject", params: ReportRequest{}, wantErr: "subject is required"},
+		{name: "too many items", params: ReportRequest{Subject: "disk", Items: maxReportItems + 1}, wantErr: "items must be"},
+		{name: "negative items", params: ReportRequest{Subject: "disk", Items: -1}, wantErr: "items must be"},
+		{name: "unknown status", params: ReportRequest{Subject: "disk", Status: "fine"}, wantErr: "status must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &reportState{sequences: map[string]int{}}
+			report, err := state.buildReport(tt.params)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, validateReport(t, report))
+		})
+	}
+}
+
+func TestBuildReport_SequencePerSubject(t *testing.T) {
+	state := &reportState{sequences: map[string]int{}}
+	for _, want := range []struct {
+		subject  string
+		sequence int
+	}{{"a", 1}, {"a", 2}, {"b", 1}, {"a", 3}} {
+		report, err := state.buildReport(ReportRequest{Subject: want.subject})
+		require.NoError(t, err)
+		assert.Equal(t, want.sequence, report.Sequence, "subject %q", want.subject)
+	}
+}
+
+func TestViolateReport(t *testing.T) {
+	state := &reportState{sequences: map[string]int{}}
+	report, err := state.buildReport(ReportRequest{Subject: "disk"})
+	require.NoError(t, err)
+
+	for _, violation := range schemaViolations {
+		t.Run(violation, func(t *testing.T) {
+			out, err := violateReport(report, violation)
+			require.NoError(t, err)
+			assert.Error(t, validateReport(t, out))
+		})
+	}
+}
+
+func TestStructuredTools(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	session := connectInMemory(ctx, t, nil, addStructuredTools, nil)
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 2)
+	for _, tool := range tools.Tools {
+		assert.NotNil(t, tool.OutputSchema, "%s should declare an output schema", tool.Name)
+	}
+
+	t.Run("structured_report conforms and counts", func(t *testing.T) {
+		for _, sequence := range []float64{1, 2} {
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "structured_report",
+				Arguments: map[string]any{"subject": "disk", "owner": nil},
+			})
+			require.NoError(t, err)
+			require.False(t, result.IsError)
+			assert.NoError(t, validateReport(t, result.StructuredContent))
+			assert.Equal(t, sequence, result.StructuredContent.(map[string]any)["sequence"])
+		}
+	})
+
+	t.Run("structured_violation breaks the schema", func(t *testing.T) {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "structured_violation",
+			Arguments: map[string]any{"subject": "disk", "violation": violationBadEnum},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		assert.Equal(t, "unknown", result.StructuredContent.(map[string]any)["status"])
+		assert.Error(t, validateReport(t, result.StructuredContent))
+	})
+
+	t.Run("structured_violation rejects unknown violations", func(t *testing.T) {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name:      "structured_violation",
+			Arguments: map[string]any{"subject": "disk", "violation": "nope"},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+	})
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// liveResourceURI is the resource whose contents change: each touch bumps
+// its revision and notifies its subscribers.
+const liveResourceURI = "yardstick://live/counter"
+
+// TouchResourceResponse represents the response from the touch_resource
+// tool
+type TouchResourceResponse struct {
+	URI      string `json:"uri"`
+	Revision int    `json:"revision"`
+	// Subscribers is how many sessions were subscribed to the resource when
+	// it was touched, so a subscribe a gateway swallowed shows up as a
+	// missing subscriber rather than as a silent lack of updates.
+	Subscribers int `json:"subscribers"`
+}
+
+// liveResource holds the live resource's revision and who subscribed to
+// what. The SDK keeps its own subscription table to route
+// notifications/resources/updated; this one only exists to be reported.
+type liveResource struct {
+	server *mcp.Server
+
+	mu            sync.Mutex
+	revision      int
+	subscriptions map[string]map[mcp.Session]bool
+}
+
+func newLiveResource() *liveResource {
+	return &liveResource{subscriptions: map[string]map[mcp.Session]bool{}}
+}
+
+// serverOptions returns the subscribe and unsubscribe handlers, which the
+// SDK only takes at construction. Setting them is also what makes it
+// advertise the resources.subscribe capability. On the 2026-07-28 protocol
+// the SDK calls them for each URI a subscriptions/listen stream names, when
+// the stream opens and when it closes.
+func (l *liveResource) serverOptions() *mcp.ServerOptions {
+	return &mcp.ServerOptions{SubscribeHandler: l.subscribe, UnsubscribeHandler: l.unsubscribe}
+}
+
+// subscribe accepts a subscription to any URI, like the spec's servers
+// that can't tell which resources exist up front. Only the live resource
+// ever sends updates.
+func (l *liveResource) subscribe(_ context.Context, req *mcp.SubscribeRequest) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sessions := l.subscriptions[req.Params.URI]
+	if sessions == nil {
+		sessions = map[mcp.Session]bool{}
+		l.subscriptions[req.Params.URI] = sessions
+	}
+	sessions[req.Session] = true
+	return nil
+}
+
+func (l *liveResource) unsubscribe(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.subscriptions[req.Params.URI], req.Session)
+	if len(l.subscriptions[req.Params.URI]) == 0 {
+		delete(l.subscriptions, req.Params.URI)
+	}
+	return nil
+}
+
+// connectedSessions returns the set of server's sessions that are still
+// connected, for dropping state kept per session once it ends.
+func connectedSessions(server *mcp.Server) map[mcp.Session]bool {
+	connected := map[mcp.Session]bool{}
+	for session := range server.Sessions() {
+		connected[session] = true
+	}
+	return connected
+}
+
+// touch bumps the live resource's revision and notifies its subscribers.
+// Subscriptions of sessions that ended without unsubscribing are dropped
+// first, as the SDK drops them from its own table.
+func (l *liveResource) touch(ctx context.Context) (TouchResourceResponse, error) {
+	connected := connectedSessions(l.server)
+
+	l.mu.Lock()
+	for uri, sessions := range l.subscriptions {
+		maps.DeleteFunc(sessions, func(session mcp.Session, _ bool) bool { return !connected[session] })
+		if len(sessions) == 0 {
+			delete(l.subscriptions, uri)
+		}
+	}
+	l.revision++
+	response := TouchResourceResponse{
+		URI:         liveResourceURI,
+		Revision:    l.revision,
+		Subscribers: len(l.subscriptions[liveResourceURI]),
+	}
+	l.mu.Unlock()
+
+	if err := l.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: liveResourceURI}); err != nil {
+		return TouchResourceResponse{}, fmt.Errorf("failed to notify subscribers: %w", err)
+	}
+	return response, nil
+}
+
+// run touches the live resource every interval until ctx is done.
+func (l *liveResource) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := l.touch(ctx); err != nil {
+				log.Printf("Touching %s: %v", liveResourceURI, err)
+			}
+		}
+	}
+}
+
+func (l *liveResource) read(_ context.Context, _ *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{Text: fmt.Sprintf("revision %d\n", l.revision)}}}, nil
+}
+
+func (l *liveResource) touchHandler(
+	ctx context.Context, _ *mcp.CallToolRequest, _ struct{},
+) (*mcp.CallToolResult, TouchResourceResponse, error) {
+	response, err := l.touch(ctx)
+	return nil, response, err
+}
+
+// addLiveResource registers the live resource and touch_resource on
+// server, which must have been created with l.serverOptions().
+func addLiveResource(server *mcp.Server, l *liveResource) {
+	l.server = server
+	server.AddResource(&mcp.Resource{
+		URI:         liveResourceURI,
+		Name:        "counter",
+		MIMEType:    mimeText,
+		Description: "A revision counter that changes whenever touch_resource is called, and on a timer if configured",
+	}, l.read)
+
+	addTool(server, &mcp.Tool{
+		Name: "touch_resource",
+		Description: "Bump the revision of " + liveResourceURI + " and send notifications/resources/updated " +
+			"to every session subscribed to it. Reports the new revision and how many sessions were subscribed.",
+		InputSchema: objectSchema(nil, map[string]*jsonschema.Schema{}),
+	}, l.touchHandler)
+}
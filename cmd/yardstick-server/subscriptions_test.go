@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+func newLiveServer() (*mcp.Server, *liveResource) {
+	live := newLiveResource()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, live.serverOptions())
+	addLiveResource(server, live)
+	return server, live
+}
+
+// TestLiveResource_LegacySubscribe covers resources/subscribe and
+// resources/unsubscribe, which only protocols before 2026-07-28 have.
+func TestLiveResource_LegacySubscribe(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server, _ := newLiveServer()
+	c := newLegacyConn(ctx, t, server)
+	touch := map[string]any{"name": "touch_resource", "arguments": map[string]any{}}
+
+	resp, _ := c.call(ctx, "resources/subscribe", map[string]any{"uri": liveResourceURI})
+	require.NoError(t, resp.Error)
+
+	resp, notifications := c.call(ctx, "tools/call", touch)
+	require.NoError(t, resp.Error)
+	var result mcp.CallToolResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.JSONEq(t, `{"uri":"`+liveResourceURI+`","revision":1,"subscribers":1}`, mustJSON(t, result.StructuredContent))
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "notifications/resources/updated", notifications[0].Method)
+	assert.JSONEq(t, `{"uri":"`+liveResourceURI+`"}`, string(notifications[0].Params))
+
+	resp, _ = c.call(ctx, "resources/unsubscribe", map[string]any{"uri": liveResourceURI})
+	require.NoError(t, resp.Error)
+	resp, notifications = c.call(ctx, "tools/call", touch)
+	require.NoError(t, resp.Error)
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.JSONEq(t, `{"uri":"`+liveResourceURI+`","revision":2,"subscribers":0}`, mustJSON(t, result.StructuredContent))
+	assert.Empty(t, notifications)
+
+	resp, _ = c.call(ctx, "resources/read", map[string]any{"uri": liveResourceURI})
+	require.NoError(t, resp.Error)
+	assert.Contains(t, string(resp.Result), `"text":"revision 2\n"`)
+}
+
+// TestLiveResource_Transports subscribes with the SDK's client, which uses
+// subscriptions/listen on the 2026-07-28 protocol, over each HTTP-based
+// transport, and checks updates arrive for touches by the tool and the
+// timer but stop after unsubscribing.
+func TestLiveResource_Transports(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server, live := newLiveServer()
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	transports := map[string]func() mcp.Transport{
+		transportSSE: func() mcp.Transport { return &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath} },
+		transportStreamableHTTP: func() mcp.Transport {
+			return &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath}
+		},
+		transportWebSocket: func() mcp.Transport { return &wsconn.ClientTransport{URL: httpServer.URL + wsPath} },
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var updates []string
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, &mcp.ClientOptions{
+				ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+					mu.Lock()
+					defer mu.Unlock()
+					updates = append(updates, req.Params.URI)
+				},
+			})
+			session, err := client.Connect(ctx, transport(), nil)
+			require.NoError(t, err)
+			defer session.Close()
+			count := func() int {
+				mu.Lock()
+				defer mu.Unlock()
+				return len(updates)
+			}
+
+			require.NoError(t, session.Subscribe(ctx, &mcp.SubscribeParams{URI: liveResourceURI}))
+			// On the 2026-07-28 protocol the subscription is opened in the
+			// background, so touch until the first update gets through.
+			require.Eventually(t, func() bool {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "touch_resource", Arguments: map[string]any{}})
+				return err == nil && !result.IsError && count() > 0
+			}, 2*time.Second, 50*time.Millisecond)
+
+			runCtx, stop := context.WithCancel(ctx)
+			go live.run(runCtx, 10*time.Millisecond)
+			seen := count()
+			require.Eventually(t, func() bool { return count() >= seen+3 }, 2*time.Second, 10*time.Millisecond)
+			stop()
+
+			require.NoError(t, session.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: liveResourceURI}))
+			require.Eventually(t, func() bool {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "touch_resource", Arguments: map[string]any{}})
+				return err == nil && result.StructuredContent.(map[string]any)["subscribers"] == 0.0
+			}, 2*time.Second, 50*time.Millisecond)
+			seen = count()
+			_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "touch_resource", Arguments: map[string]any{}})
+			require.NoError(t, err)
+			time.Sleep(50 * time.Millisecond)
+			assert.Equal(t, seen, count())
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, uri := range updates {
+				assert.Equal(t, liveResourceURI, uri)
+			}
+		})
+	}
+}
+
+// TestLiveResource_ClosedSessions checks a session that ends without
+// unsubscribing stops being counted.
+func TestLiveResource_ClosedSessions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	server, live := newLiveServer()
+
+	serverTransport, _ := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	require.NoError(t, live.subscribe(ctx, &mcp.SubscribeRequest{
+		Session: serverSession, Params: &mcp.SubscribeParams{URI: liveResourceURI},
+	}))
+	got, err := live.touch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Subscribers)
+
+	require.NoError(t, serverSession.Close())
+	require.Eventually(t, func() bool {
+		got, err := live.touch(ctx)
+		return err == nil && got.Subscribers == 0
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	transportStdio          = "stdio"
+	transportSSE            = "sse"
+	transportStreamableHTTP = "streamable-http"
+	transportWebSocket      = "websocket"
+
+	// transportBoth serves every HTTP-based transport (SSE,
+	// streamable-http and WebSocket) from one http.Server, so all of them
+	// see the same mcp.Server and fault-injection state.
+	transportBoth = "both"
+
+	defaultSSEPath = "/sse"
+	defaultMCPPath = "/mcp"
+	defaultWSPath  = "/ws"
+
+	// unixSocketPrefix marks a BIND_ADDRESS naming a unix socket
+	// (unix:///path/to.sock) rather than a TCP host.
+	unixSocketPrefix = "unix://"
+)
+
+// validatePathConfig checks SSE_PATH, MCP_PATH, WS_PATH and BASE_PATH
+// (already stripped of a trailing slash) before they are mounted, since
+// ServeMux would otherwise panic on a malformed pattern or silently let one
+// transport shadow another.
+func validatePathConfig(sse, mcp, ws, base string) error {
+	paths := []struct{ name, path string }{{"SSE_PATH", sse}, {"MCP_PATH", mcp}, {"WS_PATH", ws}}
+	for i, p := range paths {
+		if !strings.HasPrefix(p.path, "/") {
+			return fmt.Errorf("%s must start with \"/\" (got %q)", p.name, p.path)
+		}
+		for _, q := range paths[:i] {
+			if p.path == q.path {
+				return fmt.Errorf("%s and %s must differ (both are %q)", q.name, p.name, p.path)
+			}
+		}
+	}
+	if base != "" && !strings.HasPrefix(base, "/") {
+		return fmt.Errorf("BASE_PATH must start with \"/\" (got %q)", base)
+	}
+	return nil
+}
+
+// endpointPath returns the path p is served at, under BASE_PATH. The
+// prefix is not stripped before reaching the SDK handlers, so the SSE
+// endpoint event advertises the full prefixed path back to the client.
+func endpointPath(p string) string {
+	return basePath + p
+}
+
+// unixSocketPath returns the socket path of a unix:// BIND_ADDRESS, and
+// whether BIND_ADDRESS names a socket at all.
+func unixSocketPath() (string, bool) {
+	return strings.CutPrefix(bindAddress, unixSocketPrefix)
+}
+
+// listen opens the listener the HTTP transports are served on: a unix
+// socket for a unix:// BIND_ADDRESS, or TCP on BIND_ADDRESS (all
+// interfaces when empty) and the configured port. A stale socket file left
+// behind by a previous run is removed first; anything else at that path is
+// left alone and makes listen fail.
+func listen() (net.Listener, error) {
+	if path, ok := unixSocketPath(); ok {
+		if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(path); err != nil {
+				return nil, fmt.Errorf("removing stale socket %s: %w", path, err)
+			}
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", net.JoinHostPort(bindAddress, strconv.Itoa(port)))
+}
+
+// endpointURL describes where path is reachable, for the startup log.
+func endpointURL(path string) string {
+	if socket, ok := unixSocketPath(); ok {
+		return fmt.Sprintf("%s%s (path %s)", unixSocketPrefix, socket, endpointPath(path))
+	}
+	host := bindAddress
+	if host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("http://%s%s", net.JoinHostPort(host, strconv.Itoa(port)), endpointPath(path))
+}
+
+// newMux mounts the HTTP transports selected by t (sse, streamable-http,
+// websocket, or both, meaning all three) on a fresh ServeMux, each behind the same origin and auth wrappers
+// and all connected to server.
+func newMux(server *mcp.Server, t string) *http.ServeMux {
+	mux := http.NewServeMux()
+	getServer := func(_ *http.Request) *mcp.Server {
+		return server
+	}
+
+	if t == transportSSE || t == transportBoth {
+		handler := mcp.NewSSEHandler(getServer, &mcp.SSEOptions{
+			DisableLocalhostProtection: corsMode == corsModePermissive,
+		})
+		// The SSE handler serves both GET (SSE stream) and POST (messages) requests
+		mux.Handle(endpointPath(ssePath), noWriteDeadline(originWrapper(newSSECredentials().wrap(bearerWrapper(handler)))))
+		log.Printf("SSE endpoint: %s", endpointURL(ssePath))
+	}
+	if t == transportStreamableHTTP || t == transportBoth {
+		handler := mcp.NewStreamableHTTPHandler(getServer, &mcp.StreamableHTTPOptions{
+			Stateless:                  stateless,
+			DisableLocalhostProtection: corsMode == corsModePermissive,
+		})
+		mux.Handle(endpointPath(mcpPath), noWriteDeadline(originWrapper(authWrapper(bearerWrapper(handler)))))
+		log.Printf("Streamable HTTP endpoint: %s (stateless=%t)", endpointURL(mcpPath), stateless)
+	}
+	if t == transportWebSocket || t == transportBoth {
+		mux.Handle(endpointPath(wsPath), originWrapper(authWrapper(bearerWrapper(newWebSocketHandler(getServer)))))
+		log.Printf("WebSocket endpoint: %s", endpointURL(wsPath))
+	}
+	return mux
+}
+
+// noWriteDeadline clears the http.Server's write deadline for a request. An
+// SSE stream, or a streamable-http POST answering a tool call, stays open
+// as long as the session or the tool runs: wait_for_cancel and sample for
+// up to ten minutes, and progress for up to about 17 hours (1000 steps of
+// a minute each), so WriteTimeout would cut them off mid-response. wsconn.Handler does the
+// same for WebSocket connections.
+func noWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		next.ServeHTTP(w, r)
+	})
+}
+
+// serveHTTP serves handler on the configured listener (see listen) until
+// it fails.
+func serveHTTP(handler http.Handler) error {
+	ln, err := listen()
+	if err != nil {
+		return err
+	}
+	// Create server with timeouts to address G114 gosec issue
+	srv := &http.Server{
+		Handler:      handler,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+	return srv.Serve(ln)
+}
+
+type stdioSessionKey struct{}
+
+// runStdio serves server over stdin/stdout until the client disconnects.
+// The session's context is marked (see isStdioSession) so stdio-only
+// middleware can tell it apart from HTTP sessions on the same server.
+func runStdio(ctx context.Context, server *mcp.Server) error {
+	return server.Run(context.WithValue(ctx, stdioSessionKey{}, true), &mcp.StdioTransport{})
+}
+
+// isStdioSession reports whether ctx belongs to a session started by
+// runStdio. The SDK derives every request's context from the context the
+// session was connected with, so the mark carries through to middleware
+// and tool handlers.
+func isStdioSession(ctx context.Context) bool {
+	v, _ := ctx.Value(stdioSessionKey{}).(bool)
+	return v
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+// withEndpointConfig sets the endpoint path and listener globals for the
+// duration of a test.
+func withEndpointConfig(t *testing.T, sse, mcp, base, bind string) {
+	t.Helper()
+	origSSE, origMCP, origBase, origBind := ssePath, mcpPath, basePath, bindAddress
+	t.Cleanup(func() {
+		ssePath, mcpPath, basePath, bindAddress = origSSE, origMCP, origBase, origBind
+	})
+	ssePath, mcpPath, basePath, bindAddress = sse, mcp, base, bind
+}
+
+func TestValidatePathConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		sse     string
+		mcp     string
+		ws      string
+		base    string
+		wantErr string
+	}{
+		{name: "defaults", sse: "/sse", mcp: "/mcp", ws: "/ws"},
+		{name: "custom paths under a base", sse: "/events", mcp: "/rpc", ws: "/socket", base: "/tenant-a"},
+		{name: "relative sse path", sse: "sse", mcp: "/mcp", ws: "/ws", wantErr: "SSE_PATH"},
+		{name: "relative mcp path", sse: "/sse", mcp: "mcp", ws: "/ws", wantErr: "MCP_PATH"},
+		{name: "relative ws path", sse: "/sse", mcp: "/mcp", ws: "ws", wantErr: "WS_PATH"},
+		{name: "relative base path", sse: "/sse", mcp: "/mcp", ws: "/ws", base: "tenant-a", wantErr: "BASE_PATH"},
+		{name: "same path for sse and mcp", sse: "/mcp", mcp: "/mcp", ws: "/ws", wantErr: "SSE_PATH and MCP_PATH must differ"},
+		{name: "same path for mcp and ws", sse: "/sse", mcp: "/rpc", ws: "/rpc", wantErr: "MCP_PATH and WS_PATH must differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePathConfig(tt.sse, tt.mcp, tt.ws, tt.base)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMux_CustomPaths(t *testing.T) {
+	withEndpointConfig(t, "/events", "/rpc", "/tenant-a", "")
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mux := newMux(server, transportBoth)
+
+	for _, path := range []string{"/tenant-a/events", "/tenant-a/rpc"} {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, path, pattern)
+	}
+	for _, path := range []string{"/sse", "/mcp", "/events", "/rpc"} {
+		_, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Empty(t, pattern, "%s should not be mounted", path)
+	}
+}
+
+func TestNewMux_Routes(t *testing.T) {
+	tests := []struct {
+		transport string
+		wantSSE   bool
+		wantMCP   bool
+		wantWS    bool
+	}{
+		{transport: transportSSE, wantSSE: true},
+		{transport: transportStreamableHTTP, wantMCP: true},
+		{transport: transportWebSocket, wantWS: true},
+		{transport: transportBoth, wantSSE: true, wantMCP: true, wantWS: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.transport, func(t *testing.T) {
+			server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+			mux := newMux(server, tt.transport)
+
+			for path, want := range map[string]bool{ssePath: tt.wantSSE, mcpPath: tt.wantMCP, wsPath: tt.wantWS} {
+				_, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, path, nil))
+				if want {
+					assert.Equal(t, path, pattern, "%s should be mounted", path)
+				} else {
+					assert.Empty(t, pattern, "%s should not be mounted", path)
+				}
+			}
+		})
+	}
+}
+
+// TestNewMux_BothShareServer connects an SSE, a streamable-http and a
+// WebSocket client to one "both" mux and checks that both are served by the one
+// mcp.Server passed in, which is what lets fault injection count calls
+// across transports.
+func TestNewMux_BothShareServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, echoHandler)
+	httpServer := httptest.NewServer(newMux(server, transportBoth))
+	defer httpServer.Close()
+
+	transports := map[string]mcp.Transport{
+		transportSSE:            &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath},
+		transportStreamableHTTP: &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath},
+		transportWebSocket:      &wsconn.ClientTransport{URL: httpServer.URL + wsPath},
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport, nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "echo",
+				Arguments: map[string]any{"input": "hello"},
+			})
+			require.NoError(t, err)
+			assert.False(t, result.IsError)
+		})
+	}
+}
+
+// TestServeHTTP_UnixSocket serves both transports on a unix socket under a
+// base path and connects to each through it, covering the SSE endpoint
+// event advertising the prefixed path back to the client.
+func TestServeHTTP_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "yardstick.sock")
+	withEndpointConfig(t, defaultSSEPath, defaultMCPPath, "/prefix", unixSocketPrefix+socket)
+
+	// A socket left behind by an earlier run must not stop the listener.
+	stale, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, echoHandler)
+	go func() { _ = serveHTTP(newMux(server, transportBoth)) }()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transports := map[string]mcp.Transport{
+		transportSSE:            &mcp.SSEClientTransport{Endpoint: "http://yardstick/prefix/sse", HTTPClient: httpClient},
+		transportStreamableHTTP: &mcp.StreamableClientTransport{Endpoint: "http://yardstick/prefix/mcp", HTTPClient: httpClient},
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport, nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			_, err = session.ListTools(ctx, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestListen_RefusesNonSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-socket")
+	require.NoError(t, os.WriteFile(path, []byte("keep me"), 0o600))
+	withEndpointConfig(t, defaultSSEPath, defaultMCPPath, "", unixSocketPrefix+path)
+
+	_, err := listen()
+	assert.Error(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "keep me", string(data))
+}
+
+func TestIsStdioSession(t *testing.T) {
+	assert.False(t, isStdioSession(context.Background()))
+	assert.True(t, isStdioSession(context.WithValue(context.Background(), stdioSessionKey{}, true)))
+}
+
+// TestNewMux_OutlivesWriteTimeout runs a tool call for longer than the
+// http.Server's WriteTimeout over SSE and streamable-http, and checks the
+// result still arrives.
+func TestNewMux_OutlivesWriteTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	addCancellationTools(server)
+	httpServer := httptest.NewUnstartedServer(newMux(server, transportBoth))
+	httpServer.Config.ReadTimeout = 200 * time.Millisecond
+	httpServer.Config.WriteTimeout = 200 * time.Millisecond
+	httpServer.Start()
+	defer httpServer.Close()
+
+	transports := map[string]mcp.Transport{
+		transportSSE:            &mcp.SSEClientTransport{Endpoint: httpServer.URL + ssePath},
+		transportStreamableHTTP: &mcp.StreamableClientTransport{Endpoint: httpServer.URL + mcpPath},
+	}
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+			session, err := client.Connect(ctx, transport, nil)
+			require.NoError(t, err)
+			defer session.Close()
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{
+				Name:      "wait_for_cancel",
+				Arguments: map[string]any{"timeout_ms": 600},
+			})
+			require.NoError(t, err)
+			require.False(t, result.IsError, "%v", result.Content)
+			assert.GreaterOrEqual(t, result.StructuredContent.(map[string]any)["ran_ms"], float64(600))
+		})
+	}
+}
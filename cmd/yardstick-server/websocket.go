@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+type websocketSessionKey struct{}
+
+// newWebSocketHandler serves MCP over WebSocket (see wsconn). Sessions are
+// marked (see isWebSocketSession) so websocketScopeMiddleware can find them.
+//
+// The library's own same-origin check on the upgrade is kept only when
+// CORS_MODE is off; otherwise originWrapper has already applied the
+// configured policy, and the library's check would override permissive
+// mode.
+func newWebSocketHandler(getServer func(*http.Request) *mcp.Server) http.Handler {
+	handler := wsconn.NewHandler(getServer, &wsconn.HandlerOptions{
+		InsecureSkipVerify: corsMode != corsModeOff,
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), websocketSessionKey{}, true)))
+	})
+}
+
+// isWebSocketSession reports whether ctx belongs to a session served by
+// newWebSocketHandler.
+func isWebSocketSession(ctx context.Context) bool {
+	v, _ := ctx.Value(websocketSessionKey{}).(bool)
+	return v
+}
+
+// websocketScopeMiddleware enforces per-tool scopes (see toolScopes) on
+// WebSocket sessions. scopeWrapper can't: it inspects HTTP request bodies,
+// and a WebSocket carries every message after the upgrade on one
+// connection. The bearer token is still verified on the upgrade by
+// bearerWrapper, which leaves it in the session's context; a call lacking
+// scopes fails with a JSON-RPC error naming them, since there is no HTTP
+// response left to carry a 403.
+func websocketScopeMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method != methodToolsCall || !isWebSocketSession(ctx) {
+			return next(ctx, method, req)
+		}
+		params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+		if !ok || params == nil {
+			return next(ctx, method, req)
+		}
+		var granted []string
+		if tokenInfo := auth.TokenInfoFromContext(ctx); tokenInfo != nil {
+			granted = tokenInfo.Scopes
+		}
+		if missing := missingScopes([]string{params.Name}, granted); len(missing) > 0 {
+			return nil, fmt.Errorf("insufficient_scope: tool %q requires scope %q", params.Name, strings.Join(missing, " "))
+		}
+		return next(ctx, method, req)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stackloklabs/yardstick/internal/wsconn"
+)
+
+// newWebSocketTestServer serves echo and whoami over WebSocket only,
+// behind the same wrappers and scope middleware main installs.
+func newWebSocketTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"}, echoHandler)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"}, whoamiHandler)
+	server.AddReceivingMiddleware(websocketScopeMiddleware)
+	httpServer := httptest.NewServer(newMux(server, transportWebSocket))
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func connectWebSocket(ctx context.Context, t *testing.T, url string, header http.Header) (*mcp.ClientSession, error) {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+	session, err := client.Connect(ctx, &wsconn.ClientTransport{URL: url + wsPath, Header: header}, nil)
+	if err == nil {
+		t.Cleanup(func() { session.Close() })
+	}
+	return session, err
+}
+
+func TestWebSocket_AuthWrapper(t *testing.T) {
+	withCredentials(t, "X-Auth-Token", "secret123", nil)
+	httpServer := newWebSocketTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := connectWebSocket(ctx, t, httpServer.URL, nil)
+	assert.Error(t, err, "the upgrade should be rejected without a credential")
+
+	session, err := connectWebSocket(ctx, t, httpServer.URL, http.Header{"X-Auth-Token": {"secret123"}})
+	require.NoError(t, err)
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami"})
+	require.NoError(t, err)
+	assert.Equal(t, credentialNameDefault, result.StructuredContent.(map[string]any)["credential"])
+}
+
+func TestWebSocket_ToolScopes(t *testing.T) {
+	withAuthTokens(t,
+		map[string][]string{"reader": {"tools:read"}, "writer": {"tools:read", "tools:echo"}},
+		map[string][]string{"echo": {"tools:echo"}})
+	httpServer := newWebSocketTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		token   string
+		wantErr bool
+	}{
+		{token: "reader", wantErr: true},
+		{token: "writer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			session, err := connectWebSocket(ctx, t, httpServer.URL, http.Header{"Authorization": {"Bearer " + tt.token}})
+			require.NoError(t, err)
+
+			_, err = session.CallTool(ctx, &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"input": "hello"}})
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "insufficient_scope")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "whoami"})
+			require.NoError(t, err)
+			assert.Len(t, result.StructuredContent.(map[string]any)["scopes"], len(authTokens[tt.token]))
+		})
+	}
+}
+
+func TestWebSocketScopeMiddleware_IgnoresOtherSessions(t *testing.T) {
+	withAuthTokens(t, map[string][]string{"reader": {"tools:read"}}, map[string][]string{"echo": {"tools:echo"}})
+	handler := websocketScopeMiddleware(noopHandler)
+
+	// Other HTTP transports are checked by scopeWrapper instead.
+	_, err := handler(context.Background(), methodToolsCall, &mcp.ServerRequest[*mcp.CallToolParamsRaw]{
+		Params: &mcp.CallToolParamsRaw{Name: "echo"},
+	})
+	assert.NoError(t, err)
+}
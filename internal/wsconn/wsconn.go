@@ -0,0 +1,159 @@
+// Package wsconn carries MCP JSON-RPC messages over a WebSocket, one
+// message per text frame. The go-sdk has no WebSocket transport, so this
+// provides both ends of one for the yardstick server and client.
+package wsconn
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Subprotocol is the WebSocket subprotocol both ends offer. It isn't
+// required: a peer that doesn't negotiate it is still served.
+const Subprotocol = "mcp"
+
+// conn adapts a WebSocket to mcp.Connection.
+type conn struct {
+	ws *websocket.Conn
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newConn wraps ws. The library's default 32KiB read limit is lifted, since
+// MCP puts no bound on message size and the other transports don't either.
+func newConn(ws *websocket.Conn) *conn {
+	ws.SetReadLimit(-1)
+	return &conn{ws: ws}
+}
+
+// Read implements mcp.Connection. Binary frames are rejected: JSON-RPC is
+// text, and accepting them would hide a misbehaving peer.
+func (c *conn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	typ, data, err := c.ws.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if typ != websocket.MessageText {
+		return nil, fmt.Errorf("unexpected %s frame: JSON-RPC messages must be sent as text", typ)
+	}
+	return jsonrpc.DecodeMessage(data)
+}
+
+// Write implements mcp.Connection.
+func (c *conn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	return c.ws.Write(ctx, websocket.MessageText, data)
+}
+
+// Close implements mcp.Connection, performing the closing handshake once.
+func (c *conn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.ws.Close(websocket.StatusNormalClosure, "")
+	})
+	return c.closeErr
+}
+
+// SessionID implements mcp.Connection. WebSocket sessions are identified by
+// the connection itself, so there is no separate ID.
+func (c *conn) SessionID() string { return "" }
+
+// connTransport is an mcp.Transport for an already-established WebSocket.
+type connTransport struct {
+	conn *conn
+}
+
+func (t *connTransport) Connect(context.Context) (mcp.Connection, error) {
+	return t.conn, nil
+}
+
+// ClientTransport is an mcp.Transport that dials a WebSocket endpoint.
+type ClientTransport struct {
+	// URL is the ws:// or wss:// endpoint (http:// and https:// work too).
+	URL string
+	// HTTPClient is used for the opening handshake. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+	// Header is sent with the opening handshake, e.g. for authentication.
+	Header http.Header
+}
+
+// Connect implements mcp.Transport.
+func (t *ClientTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	ws, _, err := websocket.Dial(ctx, t.URL, &websocket.DialOptions{
+		HTTPClient:   t.HTTPClient,
+		HTTPHeader:   t.Header,
+		Subprotocols: []string{Subprotocol},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newConn(ws), nil
+}
+
+// HandlerOptions configures a Handler.
+type HandlerOptions struct {
+	// InsecureSkipVerify disables the library's same-origin check on the
+	// opening handshake, for servers that validate Origin themselves (or
+	// deliberately don't).
+	InsecureSkipVerify bool
+}
+
+// Handler serves MCP over WebSocket: each upgraded request becomes one
+// session on the server returned by getServer.
+type Handler struct {
+	getServer func(*http.Request) *mcp.Server
+	opts      HandlerOptions
+}
+
+// NewHandler returns a Handler. opts may be nil.
+func NewHandler(getServer func(*http.Request) *mcp.Server, opts *HandlerOptions) *Handler {
+	h := &Handler{getServer: getServer}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// ServeHTTP upgrades the request and serves the session until either side
+// closes it. The session is connected with the upgrade request's context,
+// as the SDK's own HTTP handlers do, so values that middleware put on the
+// request reach tool handlers.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server := h.getServer(r)
+	if server == nil {
+		http.Error(w, "no server available", http.StatusBadRequest)
+		return
+	}
+
+	// The connection outlives the http.Server's read and write timeouts,
+	// which would otherwise still apply after the hijack.
+	rc := http.NewResponseController(w)
+	_ = rc.SetReadDeadline(time.Time{})
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:       []string{Subprotocol},
+		InsecureSkipVerify: h.opts.InsecureSkipVerify,
+	})
+	if err != nil {
+		// Accept has already written the error response.
+		return
+	}
+	session, err := server.Connect(r.Context(), &connTransport{conn: newConn(ws)}, nil)
+	if err != nil {
+		ws.Close(websocket.StatusInternalError, "failed to start session")
+		return
+	}
+	_ = session.Wait()
+}
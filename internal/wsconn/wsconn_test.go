@@ -0,0 +1,92 @@
+package wsconn
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type echoArgs struct {
+	Input string `json:"input"`
+}
+
+func newTestServer(t *testing.T, opts *HandlerOptions) *httptest.Server {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo"},
+		func(_ context.Context, _ *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, echoArgs, error) {
+			return nil, args, nil
+		})
+	httpServer := httptest.NewServer(NewHandler(func(*http.Request) *mcp.Server { return server }, opts))
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func TestRoundTrip(t *testing.T) {
+	httpServer := newTestServer(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "0.0.1"}, nil)
+	session, err := client.Connect(ctx, &ClientTransport{URL: "ws" + strings.TrimPrefix(httpServer.URL, "http")}, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	// Larger than the library's default 32KiB read limit.
+	input := strings.Repeat("a", 64<<10)
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "echo", Arguments: map[string]any{"input": input}})
+	require.NoError(t, err)
+	assert.Equal(t, input, result.StructuredContent.(map[string]any)["input"])
+}
+
+func TestRejectsBinaryFrames(t *testing.T) {
+	httpServer := newTestServer(t, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, _, err := websocket.Dial(ctx, httpServer.URL, nil)
+	require.NoError(t, err)
+	defer ws.CloseNow()
+
+	require.NoError(t, ws.Write(ctx, websocket.MessageBinary, []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"}`)))
+	_, _, err = ws.Read(ctx)
+	assert.Error(t, err, "the server should drop the connection")
+}
+
+func TestOriginCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *HandlerOptions
+		wantErr bool
+	}{
+		{name: "cross-origin rejected by default", wantErr: true},
+		{name: "cross-origin allowed when skipping verification", opts: &HandlerOptions{InsecureSkipVerify: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpServer := newTestServer(t, tt.opts)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			conn, err := (&ClientTransport{
+				URL:    httpServer.URL,
+				Header: http.Header{"Origin": {"http://evil.example"}},
+			}).Connect(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				conn.Close()
+			}
+		})
+	}
+}